@@ -1,19 +1,20 @@
 package main
 
 import (
+	"context"
 	"log"
-	"net/http"
 	"time"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/google/uuid"
 
 	"github.com/kabinasoftware/liqpay-go"
+	"github.com/kabinasoftware/liqpay-go/webhook"
 )
 
 func main() {
 	cfg := liqpay.NewConfig("sandbox_", "sandbox_", true)
-	c := liqpay.NewClient(cfg, http.DefaultClient)
+	c := liqpay.NewClient(cfg, liqpay.WithTimeout(30*time.Second))
 
 	orderID := uuid.New().String()
 
@@ -25,7 +26,7 @@ func main() {
 		OrderID:       orderID,
 		Phone:         "380969696969",
 		ActionPayment: "pay",
-		ExpiredDate:   time.Now().Add(time.Hour * 5).Format(time.DateTime),
+		ExpiredDate:   liqpay.NewDateTime(time.Now().Add(time.Hour * 5)),
 		Goods: []liqpay.InvoiceItem{
 			{
 				Amount: 100,
@@ -55,7 +56,7 @@ func main() {
 		Currency:           liqpay.CurrencyUAH,
 		Description:        "test1",
 		Phone:              "380969696969",
-		SubscribeDateStart: time.Now().Format(time.DateTime),
+		SubscribeDateStart: liqpay.NewDateTime(time.Now()),
 		SubscribePeriod:    liqpay.SubscribePeriodMonthly,
 		ServerURL:          "https://2844-193-56-13-203.ngrok-free.app/callback",
 	})
@@ -88,24 +89,16 @@ func main() {
 
 	app := fiber.New()
 
-	app.Post("/callback", func(ctx *fiber.Ctx) error {
-		var (
-			data      = ctx.FormValue("data")
-			signature = ctx.FormValue("signature")
-		)
-
-		if err := c.ValidateCallback(data, signature); err != nil {
-			return err
-		}
-
-		var callback liqpay.Callback
-		if err := ctx.BodyParser(&callback); err != nil {
-			return err
-		}
-
+	callbacks := webhook.NewRouter(c)
+	callbacks.Use(webhook.Logging(log.Default()), webhook.Recover())
+	callbacks.HandleFunc(webhook.AnyAction, webhook.AnyStatus, func(ctx context.Context, callback *liqpay.Callback) error {
 		log.Printf("callback: %#+v", callback)
+		return nil
+	})
 
-		return ctx.SendStatus(fiber.StatusOK)
+	app.Post("/callback", func(ctx *fiber.Ctx) error {
+		err := callbacks.ServeCallback(ctx.UserContext(), ctx.FormValue("data"), ctx.FormValue("signature"))
+		return ctx.SendStatus(webhook.StatusCode(err))
 	})
 
 	app.Get("/test", func(ctx *fiber.Ctx) error {
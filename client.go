@@ -2,72 +2,194 @@ package liqpay
 
 import (
 	"bytes"
-	"crypto/sha1"
-	"encoding/base64"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
 	"log"
 	"net/http"
-	"net/url"
+	"strings"
+	"time"
+
+	"github.com/jim-ww/liqpay-go/signature"
 )
 
-type Client interface {
+// CheckoutClient creates checkout payments.
+type CheckoutClient interface {
 	CreateCheckout(req *CheckoutRequest) (string, error)
+}
 
+// SubscriptionClient manages regular payments.
+type SubscriptionClient interface {
 	CreateSubscription(req *SubscriptionRequest) (string, error)
 	UpdateSubscription(req *EditSubscriptionRequest) (*SubscriptionResponse, error)
 	RemoveSubscription(orderID string) (*SubscriptionResponse, error)
+}
 
+// InvoiceClient sends and cancels invoices.
+type InvoiceClient interface {
 	CreateInvoice(req *InvoiceRequest) (*InvoiceResponse, error)
 	CancelInvoice(orderID string) (*CancelInvoiceResponse, error)
+}
 
+// StatusClient queries the status of payments.
+type StatusClient interface {
 	Status(orderID string) (*StatusResponse, error)
+}
+
+// RefundClient refunds payments.
+type RefundClient interface {
 	Refund(orderID string, amount string) (*RefundResponse, error)
+}
+
+// ReportClient retrieves the payment archive and compensation registers.
+type ReportClient interface {
+	Reports(from time.Time, to time.Time) (*ReportsResponse, error)
+	CompensationRegister(day time.Time) (*CompensationResponse, error)
+	CompensationRegisterPeriod(from time.Time, to time.Time) (*CompensationResponse, error)
+	PaymentCompensation(orderID string) (*CompensationResponse, error)
+}
+
+// Caller sends requests for arbitrary actions.
+type Caller interface {
+	// Call sends a server-server request for any action and decodes the response into v.
+	Call(action Action, payload any, v any) error
+}
 
+// CallbackVerifier verifies and decodes callbacks received from LiqPay.
+type CallbackVerifier interface {
 	ValidateCallback(data string, signature string) error
+	DecodeCallback(data string, signature string) (*Callback, error)
+}
+
+// Client is the complete LiqPay client. Code that needs only part of it should accept
+// one of the interfaces it embeds, which are easier to implement in tests.
+type Client interface {
+	CheckoutClient
+	SubscriptionClient
+	InvoiceClient
+	StatusClient
+	RefundClient
+	ReportClient
+	Caller
+	CallbackVerifier
+
+	// WithMerchant returns a client that acts for the merchant with the given public key.
+	WithMerchant(publicKey string) (Client, error)
+	// Merchants returns the registry of merchants the client can act for.
+	Merchants() *MerchantRegistry
 }
 
 type client struct {
-	config     *Config
-	httpClient *http.Client
+	config         *Config
+	httpClient     *http.Client // httpClient sends server-server requests.
+	checkoutClient *http.Client // checkoutClient sends checkout requests and does not follow redirects.
+	logger         Logger
+	serverURL      string
+	checkoutURL    string
+	userAgent      string
+	skipValidation bool
+	merchants      *MerchantRegistry
+	publicKey      string
+	middleware     []Middleware
 }
 
-// NewClient creates a new LiqPay client with the provided configuration and HTTP client.
-func NewClient(config *Config, httpClient *http.Client) Client {
-	var httpC = http.DefaultClient
+// NewClient creates a new LiqPay client with the provided configuration and options.
+// Nil options are ignored, so that NewClient(config, nil) keeps working.
+func NewClient(config *Config, opts ...Option) Client {
+	o := &clientOptions{
+		httpClient:  http.DefaultClient,
+		timeout:     config.Timeout,
+		logger:      log.Default(),
+		serverURL:   config.serverURL(),
+		checkoutURL: config.checkoutURL(),
+	}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(o)
+		}
+	}
+	if o.logger == nil {
+		o.logger = log.Default()
+	}
 
-	if httpClient != nil {
-		httpC = httpClient
+	var httpC http.Client
+	if o.httpClient != nil {
+		httpC = *o.httpClient
+	}
+	if o.transport != nil {
+		httpC.Transport = o.transport
+	}
+	if o.timeout > 0 {
+		httpC.Timeout = o.timeout
 	}
 
-	httpC.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+	checkoutC := httpC
+	checkoutC.CheckRedirect = func(req *http.Request, via []*http.Request) error {
 		return http.ErrUseLastResponse
 	}
 
+	merchants := newMerchantRegistry(len(config.Merchants) + 1)
+	defaultMerchant := Merchant{PublicKey: config.PublicKey, PrivateKey: config.PrivateKey, PreviousKeys: config.PreviousKeys}
+	for _, m := range append([]Merchant{defaultMerchant}, config.Merchants...) {
+		if err := merchants.Add(m); err != nil && config.Debug {
+			o.logger.Printf("[LIQPAY DEBUG] Skipping merchant %q: %s\n", m.PublicKey, err)
+		}
+	}
+
 	return &client{
-		config:     config,
-		httpClient: httpC,
+		config:         config,
+		httpClient:     &httpC,
+		checkoutClient: &checkoutC,
+		logger:         o.logger,
+		serverURL:      o.serverURL,
+		checkoutURL:    o.checkoutURL,
+		userAgent:      o.userAgent,
+		skipValidation: o.skipValidation,
+		merchants:      merchants,
+		publicKey:      config.PublicKey,
+		middleware:     o.middleware,
 	}
 }
 
-// sign generates a hash signature for the given data using the client's private key.
-func (c client) sign(base64Data string) string {
-	hasher := sha1.New()
-	hasher.Write([]byte(c.config.PrivateKey))
-	hasher.Write([]byte(base64Data))
-	hasher.Write([]byte(c.config.PrivateKey))
-	return base64.StdEncoding.EncodeToString(hasher.Sum(nil))
+// WithMerchant returns a client that acts for the merchant with the given public key.
+func (c client) WithMerchant(publicKey string) (Client, error) {
+	if _, ok := c.merchants.Get(publicKey); !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownMerchant, publicKey)
+	}
+
+	c.publicKey = publicKey
+	return &c, nil
+}
+
+// Merchants returns the registry of merchants the client can act for.
+func (c client) Merchants() *MerchantRegistry {
+	return c.merchants
 }
 
-// encode encodes the payload to base64 format.
-func (с client) encode(payload any) (string, error) {
-	obj, err := json.Marshal(payload)
+// validate runs the request validation unless it was disabled.
+func (c client) validate(req interface{ Validate() error }) error {
+	if c.skipValidation {
+		return nil
+	}
+	return req.Validate()
+}
+
+// seal encodes and signs the payload with the key of the merchant it is addressed to.
+func (c client) seal(payload map[string]interface{}) (*signature.Envelope, error) {
+	publicKey, _ := payload["public_key"].(string)
+
+	signer, err := c.merchants.signer(publicKey)
+	if err != nil {
+		return nil, err
+	}
+
+	envelope, err := signer.Seal(payload)
 	if err != nil {
-		return "", fmt.Errorf("liqpay client: failed to encode request body: %w", err)
+		return nil, fmt.Errorf("liqpay client: failed to encode payload: %w", err)
 	}
-	return base64.StdEncoding.EncodeToString(obj), nil
+	return envelope, nil
 }
 
 // injectMissingKeys injects missing keys (version, public_key) into the payload.
@@ -81,13 +203,16 @@ func (c client) injectMissingKeys(payload any) (map[string]interface{}, error) {
 	if err := json.Unmarshal(payloadBytes, &data); err != nil {
 		return nil, err
 	}
+	if data == nil {
+		data = make(map[string]interface{})
+	}
 
 	if data["version"] == nil || data["version"] == "" {
 		data["version"] = CurrentAPIVersion
 	}
 
 	if data["public_key"] == nil || data["public_key"] == "" {
-		data["public_key"] = c.config.PublicKey
+		data["public_key"] = c.publicKey
 	}
 
 	return data, nil
@@ -97,38 +222,54 @@ func (c client) injectMissingKeys(payload any) (map[string]interface{}, error) {
 func (c client) sendClientRequest(payload any) (*http.Response, error) {
 	injectedPayload, err := c.injectMissingKeys(payload)
 	if err != nil {
-		return nil, fmt.Errorf("liqpay client: failed to inject missing keys: %w", err)
+		return nil, notSent(fmt.Errorf("liqpay client: failed to inject missing keys: %w", err))
 	}
 
-	encodedJSON, err := c.encode(injectedPayload)
+	envelope, err := c.seal(injectedPayload)
 	if err != nil {
-		return nil, fmt.Errorf("liqpay client: failed to encode payload: %w", err)
+		return nil, notSent(err)
 	}
-	signature := c.sign(encodedJSON)
+	formData := envelope.Values()
 
-	formData := url.Values{
-		"data":      {encodedJSON},
-		"signature": {signature},
+	req, err := http.NewRequest(http.MethodPost, c.checkoutURL, strings.NewReader(formData.Encode()))
+	if err != nil {
+		return nil, notSent(fmt.Errorf("liqpay client: failed to create new http request: %w", err))
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	if c.userAgent != "" {
+		req.Header.Set("User-Agent", c.userAgent)
+	}
+
+	ex := &Exchange{Action: payloadAction(injectedPayload), Payload: injectedPayload, Checkout: true, Request: req}
+	if err := c.exchange(ex, c.roundTripClient); err != nil {
+		return nil, err
 	}
 
-	resp, err := c.httpClient.PostForm(ClientServerURL, formData)
+	return ex.Response, nil
+}
+
+// roundTripClient sends a client-server request. The redirect it is answered with is not followed.
+func (c client) roundTripClient(ex *Exchange) error {
+	resp, err := c.checkoutClient.Do(ex.Request)
 	if err != nil {
-		return nil, fmt.Errorf("liqpay client: failed to parse liqpay form: %w", err)
+		return fmt.Errorf("liqpay client: failed to parse liqpay form: %w", err)
 	}
 	defer resp.Body.Close()
 
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("liqpay client: failed to read response body: %w", err)
+	}
+	ex.Response, ex.Body = resp, body
+
 	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusBadRequest {
 		if c.config.Debug {
-			bodyBytes, err := io.ReadAll(resp.Body)
-			if err != nil {
-				return nil, fmt.Errorf("liqpay client: failed to read response body: %w", err)
-			}
-			fmt.Printf("[LIQPAY DEBUG]: liqpay error response: %s\n", bodyBytes)
+			c.logger.Printf("[LIQPAY DEBUG] Error response: %s\n", body)
 		}
-		return nil, fmt.Errorf("liqpay client: unexpected status code: %d", resp.StatusCode)
+		return &HTTPError{StatusCode: resp.StatusCode, Body: body}
 	}
 
-	return resp, nil
+	return nil
 }
 
 // getClientRedirectURL extracts the redirect URL from the HTTP response.
@@ -151,77 +292,157 @@ func (c client) getClientRedirectURL(resp *http.Response) (string, error) {
 func (c client) prepareServerRequest(payload any) (*http.Request, error) {
 	injectedPayload, err := c.injectMissingKeys(payload)
 	if err != nil {
-		return nil, fmt.Errorf("liqpay client: failed to inject missing keys: %w", err)
+		return nil, notSent(fmt.Errorf("liqpay client: failed to inject missing keys: %w", err))
 	}
 
-	encodedJSON, err := c.encode(injectedPayload)
+	envelope, err := c.seal(injectedPayload)
 	if err != nil {
-		return nil, fmt.Errorf("liqpay client: failed to encode payload: %w", err)
-	}
-	signature := c.sign(encodedJSON)
-
-	formData := url.Values{
-		"data":      {encodedJSON},
-		"signature": {signature},
+		return nil, notSent(err)
 	}
+	formData := envelope.Values()
 
 	reqBody := bytes.NewBufferString(formData.Encode())
-	req, err := http.NewRequest(http.MethodPost, ServerServerURL, reqBody)
+	req, err := http.NewRequest(http.MethodPost, c.serverURL, reqBody)
 	if err != nil {
-		return nil, fmt.Errorf("liqpay client: failed to create new http request: %w", err)
+		return nil, notSent(fmt.Errorf("liqpay client: failed to create new http request: %w", err))
 	}
 
-	return req, nil
+	ctx := context.WithValue(req.Context(), actionContextKey{}, payloadAction(injectedPayload))
+	ctx = context.WithValue(ctx, payloadContextKey{}, injectedPayload)
+	return req.WithContext(ctx), nil
+}
+
+// actionContextKey is the request context key holding the action of a server-server request.
+type actionContextKey struct{}
+
+// payloadContextKey is the request context key holding the payload of a server-server request.
+type payloadContextKey struct{}
+
+// payloadAction returns the action of a payload.
+func payloadAction(payload map[string]interface{}) Action {
+	switch action := payload["action"].(type) {
+	case Action:
+		return action
+	case string:
+		return Action(action)
+	}
+	return ""
+}
+
+// retryableActions are the actions that do not move money and can be safely retried.
+var retryableActions = map[Action]bool{
+	ActionStatus:                     true,
+	ActionReports:                    true,
+	ActionCompensationRegister:       true,
+	ActionCompensationRegisterPeriod: true,
+	ActionCompensationPerPayment:     true,
+}
+
+// do sends a server-server request, retrying retryable actions according to the retry policy.
+// Once the request context is done, the request is not retried and the wait for a retry ends.
+func (c client) do(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	attempts := 1
+	if action, _ := ctx.Value(actionContextKey{}).(Action); retryableActions[action] && req.GetBody != nil {
+		attempts = c.config.Retry.MaxAttempts
+	}
+	backoff := c.config.Retry.Backoff
+
+	for attempt := 1; ; attempt++ {
+		resp, err := c.httpClient.Do(req)
+		if attempt >= attempts || ctx.Err() != nil || (err == nil && resp.StatusCode < http.StatusInternalServerError) {
+			return resp, err
+		}
+		if err == nil {
+			resp.Body.Close()
+		}
+
+		if c.config.Debug {
+			c.logger.Printf("[LIQPAY DEBUG] Retrying request, attempt %d of %d\n", attempt+1, attempts)
+		}
+		timer := time.NewTimer(backoff)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+		backoff *= 2
+
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, err
+		}
+		req = req.Clone(ctx)
+		req.Body = body
+	}
 }
 
 // sendServerRequest sends a server-server request to LiqPay API.
 func (c client) sendServerRequest(req *http.Request, v any) error {
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	if c.userAgent != "" {
+		req.Header.Set("User-Agent", c.userAgent)
+	}
+
+	action, _ := req.Context().Value(actionContextKey{}).(Action)
+	payload, _ := req.Context().Value(payloadContextKey{}).(map[string]interface{})
+	ex := &Exchange{Action: action, Payload: payload, Request: req, Result: v}
+	return c.exchange(ex, c.roundTripServer)
+}
+
+// roundTripServer sends a server-server request and decodes the response into the exchange result.
+func (c client) roundTripServer(ex *Exchange) error {
+	req, v := ex.Request, ex.Result
 	if c.config.Debug {
-		log.Printf("[LIQPAY DEBUG] Request method: %s, url: %s\n", req.Method, req.URL.String())
+		c.logger.Printf("[LIQPAY DEBUG] Request method: %s, url: %s\n", req.Method, req.URL.String())
 	}
 
-	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return fmt.Errorf("liqpay client: request failed: %w", err)
 	}
 	defer resp.Body.Close()
 
-	var res map[string]interface{}
-	if err := json.NewDecoder(resp.Body).Decode(&res); err != nil {
-		return fmt.Errorf("liqpay client: failed to decode json: %w", err)
-	}
-
-	if v == nil {
-		return nil
-	}
-
-	jsonResp, err := json.Marshal(res)
+	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return fmt.Errorf("liqpay client: failed to marshal response: %w", err)
+		return fmt.Errorf("liqpay client: failed to read response body: %w", err)
 	}
+	ex.Response, ex.Body = resp, body
 
 	if c.config.Debug {
-		log.Printf("[LIQPAY DEBUG] Response: %s", string(jsonResp))
+		c.logger.Printf("[LIQPAY DEBUG] Response: %s", body)
+	}
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return &HTTPError{StatusCode: resp.StatusCode, Body: body}
 	}
 
-	if err := json.Unmarshal(jsonResp, v); err != nil {
-		return fmt.Errorf("liqpay client: failed to unmarshal response: %w", err)
+	var res map[string]interface{}
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	if err := decoder.Decode(&res); err != nil {
+		return fmt.Errorf("liqpay client: failed to decode json: %w", err)
 	}
 
+	var apiErr *APIError
 	if res["status"] == "error" || res["status"] == "failure" || res["result"] == "error" {
-		errResp := &APIError{
-			Status: res["status"].(string),
-			Code:   res["err_code"].(string),
-			Desc:   res["err_description"].(string),
-		}
+		apiErr = newAPIError(res, body)
 
 		if c.config.Debug {
-			log.Printf("[LIQPAY DEBUG] Error status: %s, status_code: %d, code: %s, description: %s\n",
-				errResp.Status, resp.StatusCode, errResp.Code, errResp.Desc)
+			c.logger.Printf("[LIQPAY DEBUG] Error status: %s, status_code: %d, code: %s, erc: %s, description: %s\n",
+				apiErr.Status, resp.StatusCode, apiErr.Code, apiErr.Erc, apiErr.Desc)
 		}
+	}
 
-		return errResp
+	if v != nil {
+		if err := json.Unmarshal(body, v); err != nil && apiErr == nil {
+			return fmt.Errorf("liqpay client: failed to unmarshal response: %w", err)
+		}
+	}
+
+	if apiErr != nil {
+		return apiErr
 	}
 
 	return nil
@@ -231,6 +452,10 @@ func (c client) sendServerRequest(req *http.Request, v any) error {
 func (c client) CreateCheckout(data *CheckoutRequest) (string, error) {
 	data.Action = ActionPay
 
+	if err := c.validate(data); err != nil {
+		return "", err
+	}
+
 	resp, err := c.sendClientRequest(data)
 	if err != nil {
 		return "", err
@@ -249,6 +474,10 @@ func (c client) CreateSubscription(data *SubscriptionRequest) (string, error) {
 	data.Action = ActionSubscribe
 	data.Subscribe = "1"
 
+	if err := c.validate(data); err != nil {
+		return "", err
+	}
+
 	resp, err := c.sendClientRequest(data)
 	if err != nil {
 		return "", err
@@ -266,6 +495,10 @@ func (c client) CreateSubscription(data *SubscriptionRequest) (string, error) {
 func (c client) UpdateSubscription(data *EditSubscriptionRequest) (*SubscriptionResponse, error) {
 	data.Action = ActionSubscribeUpdate
 
+	if err := c.validate(data); err != nil {
+		return nil, err
+	}
+
 	req, err := c.prepareServerRequest(data)
 	if err != nil {
 		return nil, err
@@ -308,6 +541,10 @@ func (c client) RemoveSubscription(orderID string) (*SubscriptionResponse, error
 func (c client) CreateInvoice(data *InvoiceRequest) (*InvoiceResponse, error) {
 	data.Action = ActionInvoiceSend
 
+	if err := c.validate(data); err != nil {
+		return nil, err
+	}
+
 	req, err := c.prepareServerRequest(data)
 	if err != nil {
 		return nil, err
@@ -371,6 +608,10 @@ func (c client) Status(orderID string) (*StatusResponse, error) {
 func (c client) Refund(orderID string, amount string) (*RefundResponse, error) {
 	data := &RefundRequest{Action: ActionRefund, OrderID: orderID, Amount: amount}
 
+	if err := c.validate(data); err != nil {
+		return nil, err
+	}
+
 	req, err := c.prepareServerRequest(data)
 	if err != nil {
 		return nil, err
@@ -388,13 +629,74 @@ func (c client) Refund(orderID string, amount string) (*RefundResponse, error) {
 	return v, nil
 }
 
+// Call sends a server-server request for any action, e.g. one the client has no typed method for yet.
+// The payload is sent with the action, version and public_key added, and the response is decoded into v.
+// As with the typed methods, v is filled in even when an *APIError is returned.
+func (c client) Call(action Action, payload any, v any) error {
+	data, err := c.injectMissingKeys(payload)
+	if err != nil {
+		return notSent(fmt.Errorf("liqpay client: failed to inject missing keys: %w", err))
+	}
+	data["action"] = action
+
+	req, err := c.prepareServerRequest(data)
+	if err != nil {
+		return err
+	}
+
+	return c.sendServerRequest(req, v)
+}
+
+// CallAs sends a server-server request for any action and decodes the response into a new T.
+// The response is returned alongside an *APIError, and is nil for other errors.
+func CallAs[T any](c Caller, action Action, payload any) (*T, error) {
+	v := new(T)
+	err := c.Call(action, payload, v)
+	switch {
+	case err != nil && ErrorRefersToAPI(err):
+		return v, err
+	case err != nil:
+		return nil, err
+	}
+
+	return v, nil
+}
+
 // ValidateCallback validates the callback data and signature received from LiqPay.
-func (c client) ValidateCallback(data string, signature string) error {
-	expectedSignature := c.sign(data)
+// The private key is picked by the public_key field of the callback.
+func (c client) ValidateCallback(data string, sig string) error {
+	var envelope struct {
+		PublicKey string `json:"public_key"`
+	}
+	if err := signature.Decode(data, &envelope); err != nil {
+		return fmt.Errorf("liqpay client: failed to decode callback: %w", err)
+	}
+
+	publicKey := envelope.PublicKey
+	if publicKey == "" {
+		publicKey = c.publicKey
+	}
 
-	if signature != expectedSignature {
-		return errors.New("liqpay client: callback signature verification failed")
+	if err := c.merchants.verify(publicKey, data, sig); err != nil {
+		if errors.Is(err, ErrUnknownMerchant) {
+			return err
+		}
+		return fmt.Errorf("liqpay client: callback signature verification failed: %w", err)
 	}
 
 	return nil
 }
+
+// DecodeCallback validates the callback data and signature and decodes the callback.
+func (c client) DecodeCallback(data string, sig string) (*Callback, error) {
+	if err := c.ValidateCallback(data, sig); err != nil {
+		return nil, err
+	}
+
+	v := &Callback{}
+	if err := signature.Decode(data, v); err != nil {
+		return nil, fmt.Errorf("liqpay client: failed to decode callback: %w", err)
+	}
+
+	return v, nil
+}
@@ -0,0 +1,264 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/jim-ww/liqpay-go"
+	"github.com/jim-ww/liqpay-go/signature"
+	"github.com/jim-ww/liqpay-go/simulator"
+)
+
+// positional checks that exactly n positional arguments were given.
+func positional(args []string, n int, usage string) error {
+	if len(args) != n {
+		return &usageError{"usage: liqpay " + usage}
+	}
+	return nil
+}
+
+// parseFlags parses subcommand flags, reporting problems as usage errors.
+func parseFlags(flags *flag.FlagSet, args []string) error {
+	flags.SetOutput(io.Discard)
+	if err := flags.Parse(args); err != nil {
+		return &usageError{fmt.Sprintf("%s: %s", flags.Name(), err)}
+	}
+	if flags.NArg() > 0 {
+		return &usageError{fmt.Sprintf("%s: unexpected arguments %v", flags.Name(), flags.Args())}
+	}
+	return nil
+}
+
+func statusCmd(args []string, out *printer) error {
+	if err := positional(args, 1, "status <order_id>"); err != nil {
+		return err
+	}
+
+	c, _, err := newClient()
+	if err != nil {
+		return err
+	}
+
+	resp, err := c.Status(args[0])
+	return out.result(resp, err)
+}
+
+func refundCmd(args []string, out *printer) error {
+	if err := positional(args, 2, "refund <order_id> <amount>"); err != nil {
+		return err
+	}
+
+	c, _, err := newClient()
+	if err != nil {
+		return err
+	}
+
+	resp, err := c.Refund(args[0], args[1])
+	return out.result(resp, err)
+}
+
+func invoiceCmd(args []string, out *printer) error {
+	if len(args) == 0 {
+		return &usageError{"usage: liqpay invoice <send|cancel> [arguments]"}
+	}
+
+	switch args[0] {
+	case "send":
+		return invoiceSendCmd(args[1:], out)
+	case "cancel":
+		if err := positional(args[1:], 1, "invoice cancel <order_id>"); err != nil {
+			return err
+		}
+
+		c, _, err := newClient()
+		if err != nil {
+			return err
+		}
+
+		resp, err := c.CancelInvoice(args[1])
+		return out.result(resp, err)
+	}
+	return &usageError{fmt.Sprintf("unknown invoice command %q", args[0])}
+}
+
+func invoiceSendCmd(args []string, out *printer) error {
+	var (
+		req     liqpay.InvoiceRequest
+		flags   = flag.NewFlagSet("invoice send", flag.ContinueOnError)
+		expires = flags.Duration("expires", 0, "time the invoice can be paid for, e.g. 24h")
+	)
+	flags.StringVar(&req.OrderID, "order-id", "", "unique order ID")
+	flags.Float64Var(&req.Amount, "amount", 0, "payment amount")
+	flags.StringVar((*string)(&req.Currency), "currency", string(liqpay.CurrencyUAH), "payment currency")
+	flags.StringVar(&req.Description, "description", "", "payment description")
+	flags.StringVar(&req.Email, "email", "", "customer e-mail")
+	flags.StringVar(&req.Phone, "phone", "", "customer phone")
+	flags.StringVar((*string)(&req.Language), "language", "", "customer language: uk or en")
+	flags.StringVar(&req.ServerURL, "server-url", "", "callback URL")
+	if err := parseFlags(flags, args); err != nil {
+		return err
+	}
+	if *expires > 0 {
+		req.ExpiredDate = liqpay.NewDateTime(time.Now().Add(*expires))
+	}
+
+	c, _, err := newClient()
+	if err != nil {
+		return err
+	}
+
+	resp, err := c.CreateInvoice(&req)
+	return out.result(resp, err)
+}
+
+func subscriptionCmd(args []string, out *printer) error {
+	if len(args) == 0 {
+		return &usageError{"usage: liqpay subscription <update|remove> [arguments]"}
+	}
+
+	switch args[0] {
+	case "update":
+		var (
+			req   liqpay.EditSubscriptionRequest
+			flags = flag.NewFlagSet("subscription update", flag.ContinueOnError)
+		)
+		flags.StringVar(&req.OrderID, "order-id", "", "order ID of the subscription")
+		flags.Float64Var(&req.Amount, "amount", 0, "new payment amount")
+		flags.StringVar(&req.Currency, "currency", string(liqpay.CurrencyUAH), "payment currency")
+		flags.StringVar(&req.Description, "description", "", "payment description")
+		if err := parseFlags(flags, args[1:]); err != nil {
+			return err
+		}
+
+		c, _, err := newClient()
+		if err != nil {
+			return err
+		}
+
+		resp, err := c.UpdateSubscription(&req)
+		return out.result(resp, err)
+	case "remove":
+		if err := positional(args[1:], 1, "subscription remove <order_id>"); err != nil {
+			return err
+		}
+
+		c, _, err := newClient()
+		if err != nil {
+			return err
+		}
+
+		resp, err := c.RemoveSubscription(args[1])
+		return out.result(resp, err)
+	}
+	return &usageError{fmt.Sprintf("unknown subscription command %q", args[0])}
+}
+
+func signCmd(args []string, out *printer) error {
+	flags := flag.NewFlagSet("sign", flag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+	asJSON := flags.Bool("json", false, "treat the argument as a JSON payload to encode before signing")
+	if err := flags.Parse(args); err != nil {
+		return &usageError{"sign: " + err.Error()}
+	}
+	if err := positional(flags.Args(), 1, "sign [-json] <data|payload>"); err != nil {
+		return err
+	}
+
+	_, cfg, err := newClient()
+	if err != nil {
+		return err
+	}
+	signer := signature.New(cfg.PrivateKey)
+
+	if !*asJSON {
+		data := flags.Arg(0)
+		return out.print(signature.Envelope{Data: data, Signature: signer.Sign(data)})
+	}
+
+	var payload map[string]interface{}
+	if err := json.Unmarshal([]byte(flags.Arg(0)), &payload); err != nil {
+		return &usageError{"sign: payload is not a JSON object: " + err.Error()}
+	}
+	if payload["version"] == nil {
+		payload["version"] = liqpay.CurrentAPIVersion
+	}
+	if payload["public_key"] == nil {
+		payload["public_key"] = cfg.PublicKey
+	}
+
+	envelope, err := signer.Seal(payload)
+	if err != nil {
+		return err
+	}
+	return out.print(envelope)
+}
+
+func verifyCmd(args []string, out *printer) error {
+	if err := positional(args, 2, "verify <data> <signature>"); err != nil {
+		return err
+	}
+
+	c, _, err := newClient()
+	if err != nil {
+		return err
+	}
+
+	if err := c.ValidateCallback(args[0], args[1]); err != nil {
+		return err
+	}
+	return out.print(map[string]bool{"valid": true})
+}
+
+func decodeCmd(args []string, out *printer) error {
+	if err := positional(args, 1, "decode <data>"); err != nil {
+		return err
+	}
+
+	var payload json.RawMessage
+	if err := signature.Decode(args[0], &payload); err != nil {
+		return &usageError{err.Error()}
+	}
+	return out.print(payload)
+}
+
+func simulateCmd(args []string, out *printer) error {
+	var (
+		p      simulator.Payment
+		flags  = flag.NewFlagSet("simulate", flag.ContinueOnError)
+		status = flags.String("status", string(liqpay.StatusSuccess), "payment status: success, failure, reversed, subscribed, 3ds_verify...")
+	)
+	flags.SetOutput(io.Discard)
+	flags.StringVar(&p.OrderID, "order-id", "", "order ID of the payment")
+	flags.Float64Var(&p.Amount, "amount", 0, "payment amount")
+	flags.StringVar((*string)(&p.Currency), "currency", string(liqpay.CurrencyUAH), "payment currency")
+	flags.StringVar(&p.Description, "description", "", "payment description")
+	flags.StringVar((*string)(&p.Action), "action", "", "type of operation")
+	if err := flags.Parse(args); err != nil {
+		return &usageError{"simulate: " + err.Error()}
+	}
+	if err := positional(flags.Args(), 1, "simulate [flags] <url>"); err != nil {
+		return err
+	}
+
+	_, cfg, err := newClient()
+	if err != nil {
+		return err
+	}
+
+	sim := simulator.New(cfg.PublicKey, cfg.PrivateKey)
+	cb := sim.Callback(liqpay.Status(*status), p)
+	resp, err := sim.Post(flags.Arg(0), cb)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(resp.Body)
+		return &liqpay.HTTPError{StatusCode: resp.StatusCode, Body: body}
+	}
+	return out.print(map[string]any{"status_code": resp.StatusCode, "payment_id": cb.PaymentID, "order_id": cb.OrderID, "status": cb.Status})
+}
@@ -0,0 +1,174 @@
+// Command liqpay is a command-line tool for LiqPay operations and support.
+//
+// Credentials are read from the LIQPAY_* environment variables, see liqpay.LoadConfigFromEnv.
+//
+// Usage:
+//
+//	liqpay [-o json|table] <command> [arguments]
+//
+// Commands:
+//
+//	status <order_id>                          Print the payment status of an order
+//	refund <order_id> <amount>                 Refund an order
+//	invoice send [flags]                       Send an invoice
+//	invoice cancel <order_id>                  Cancel an invoice
+//	subscription update [flags]                Update a subscription
+//	subscription remove <order_id>             Remove a subscription
+//	sign [-json] <data|payload>                Sign base64 data, or encode and sign a JSON payload
+//	verify <data> <signature>                  Verify the signature of callback data
+//	decode <data>                              Print the decoded data payload
+//	simulate [flags] <url>                     Post a simulated signed callback to a webhook URL
+//
+// Exit codes:
+//
+//	0  success
+//	1  unexpected failure
+//	2  invalid usage
+//	3  invalid configuration
+//	4  request validation failed
+//	5  LiqPay API returned an error
+//	6  HTTP or network failure
+//	7  signature verification failed
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+
+	"github.com/jim-ww/liqpay-go"
+	"github.com/jim-ww/liqpay-go/signature"
+)
+
+const (
+	exitOK         = 0
+	exitFailure    = 1
+	exitUsage      = 2
+	exitConfig     = 3
+	exitValidation = 4
+	exitAPI        = 5
+	exitHTTP       = 6
+	exitSignature  = 7
+)
+
+// usageError is returned for invalid command-line usage.
+type usageError struct {
+	msg string
+}
+
+func (e *usageError) Error() string {
+	return e.msg
+}
+
+// configError is returned when the client configuration cannot be loaded.
+type configError struct {
+	err error
+}
+
+func (e *configError) Error() string {
+	return e.err.Error()
+}
+
+func (e *configError) Unwrap() error {
+	return e.err
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+// run executes the command line and returns the exit code.
+func run(args []string, stdout io.Writer, stderr io.Writer) int {
+	flags := flag.NewFlagSet("liqpay", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	format := flags.String("o", "table", "output format: json or table")
+	flags.Usage = func() {
+		fmt.Fprintln(stderr, "usage: liqpay [-o json|table] <status|refund|invoice|subscription|sign|verify|decode|simulate> [arguments]")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return exitUsage
+	}
+
+	out := &printer{w: stdout, format: *format}
+	if out.format != "json" && out.format != "table" {
+		fmt.Fprintf(stderr, "liqpay: unknown output format %q\n", out.format)
+		return exitUsage
+	}
+
+	err := dispatch(flags.Args(), out)
+	if err == nil {
+		return exitOK
+	}
+
+	fmt.Fprintf(stderr, "liqpay: %s\n", err)
+	return exitCode(err)
+}
+
+// dispatch runs the command named by the first argument.
+func dispatch(args []string, out *printer) error {
+	if len(args) == 0 {
+		return &usageError{"missing command"}
+	}
+
+	cmd, args := args[0], args[1:]
+	switch cmd {
+	case "status":
+		return statusCmd(args, out)
+	case "refund":
+		return refundCmd(args, out)
+	case "invoice":
+		return invoiceCmd(args, out)
+	case "subscription":
+		return subscriptionCmd(args, out)
+	case "sign":
+		return signCmd(args, out)
+	case "verify":
+		return verifyCmd(args, out)
+	case "decode":
+		return decodeCmd(args, out)
+	case "simulate":
+		return simulateCmd(args, out)
+	}
+	return &usageError{fmt.Sprintf("unknown command %q", cmd)}
+}
+
+// exitCode maps an error to the exit code of its category.
+func exitCode(err error) int {
+	var (
+		usageErr      *usageError
+		configErr     *configError
+		validationErr *liqpay.ValidationError
+		apiErr        *liqpay.APIError
+		httpErr       *liqpay.HTTPError
+		urlErr        *url.Error
+	)
+
+	switch {
+	case errors.As(err, &usageErr):
+		return exitUsage
+	case errors.As(err, &configErr):
+		return exitConfig
+	case errors.As(err, &validationErr):
+		return exitValidation
+	case errors.As(err, &apiErr):
+		return exitAPI
+	case errors.As(err, &httpErr), errors.As(err, &urlErr):
+		return exitHTTP
+	case errors.Is(err, signature.ErrInvalidSignature), errors.Is(err, liqpay.ErrUnknownMerchant):
+		return exitSignature
+	}
+	return exitFailure
+}
+
+// newClient creates a client from the environment.
+func newClient() (liqpay.Client, *liqpay.Config, error) {
+	cfg, err := liqpay.LoadConfigFromEnv()
+	if err != nil {
+		return nil, nil, &configError{err}
+	}
+	return liqpay.NewClient(cfg), cfg, nil
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"text/tabwriter"
+)
+
+// printer writes command results as JSON or as a two-column table.
+type printer struct {
+	w      io.Writer
+	format string
+}
+
+// print writes v in the selected format.
+func (p *printer) print(v any) error {
+	if p.format == "json" {
+		enc := json.NewEncoder(p.w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(v)
+	}
+
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &fields); err != nil {
+		_, err := fmt.Fprintln(p.w, string(raw))
+		return err
+	}
+
+	keys := make([]string, 0, len(fields))
+	for k, v := range fields {
+		if blank(v) {
+			continue
+		}
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	tw := tabwriter.NewWriter(p.w, 0, 4, 2, ' ', 0)
+	for _, k := range keys {
+		fmt.Fprintf(tw, "%s\t%s\n", k, tableValue(fields[k]))
+	}
+	return tw.Flush()
+}
+
+// result prints the response of an API call, which is also filled in alongside an API error.
+func (p *printer) result(v any, err error) error {
+	if v != nil && !reflect.ValueOf(v).IsNil() {
+		if perr := p.print(v); perr != nil && err == nil {
+			return perr
+		}
+	}
+	return err
+}
+
+// blank reports whether a JSON value carries no information worth a table row,
+// including empty arrays and objects.
+func blank(v json.RawMessage) bool {
+	switch string(bytes.TrimSpace(v)) {
+	case "", "null", `""`, "0", "false", "[]", "{}":
+		return true
+	}
+	return false
+}
+
+// tableValue formats a JSON value for a table cell, unquoting strings.
+func tableValue(v json.RawMessage) string {
+	var s string
+	if err := json.Unmarshal(v, &s); err == nil {
+		return s
+	}
+	return string(v)
+}
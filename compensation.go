@@ -0,0 +1,122 @@
+package liqpay
+
+import "time"
+
+// CompensationDateLayout is the layout of days in compensation register requests.
+const CompensationDateLayout = "2006-01-02"
+
+type CompensationRegisterRequest struct {
+	Action Action `json:"action"` // Transaction type
+	Date   string `json:"date"`   // Day of the register in the format 2016-04-24
+}
+
+type CompensationRegisterPeriodRequest struct {
+	Action   Action `json:"action"`    // Transaction type
+	DateFrom string `json:"date_from"` // First day of the period in the format 2016-04-24
+	DateTo   string `json:"date_to"`   // Last day of the period in the format 2016-04-24
+}
+
+type CompensationPerPaymentRequest struct {
+	Action  Action `json:"action"`   // Transaction type
+	OrderID string `json:"order_id"` // Unique purchase ID in your shop. Maximum length is 255 symbols
+}
+
+type CompensationResponse struct {
+	RawFields `json:"-"` // Raw JSON and unmodelled fields
+
+	Result string             `json:"result"` // Result of the request: success or error
+	Data   []CompensationItem `json:"data"`   // Settled payments
+}
+
+// CompensationItem is a payment settled to the merchant account.
+// OrderID and PaymentID link it to StatusResponse, ReportTransaction and Callback data.
+type CompensationItem struct {
+	RawFields `json:"-"` // Raw JSON and unmodelled fields
+
+	Action             Action    `json:"action"`              // Transaction type
+	Amount             FlexFloat `json:"amount"`              // Payment amount
+	AmountCompensation FlexFloat `json:"amount_compensation"` // Amount credited to the merchant account
+	Commission         FlexFloat `json:"commission"`          // Commission withheld from the payment
+	CompensationDate   Timestamp `json:"compensation_date"`   // Date of the settlement
+	CompensationID     FlexInt   `json:"compensation_id"`     // Settlement ID in LiqPay system
+	CreateDate         Timestamp `json:"create_date"`         // Date of payment creation
+	Currency           Currency  `json:"currency"`            // Payment currency
+	Description        string    `json:"description"`         // Payment description
+	LiqpayOrderID      string    `json:"liqpay_order_id"`     // Payment order_id in LiqPay system
+	OrderID            string    `json:"order_id"`            // Order_id payment
+	PaymentID          FlexInt   `json:"payment_id"`          // Payment id in LiqPay system
+	RefundAmount       FlexFloat `json:"refund_amount"`       // Refunded amount withheld from the settlement
+	Status             Status    `json:"status"`              // Payment status
+}
+
+func (r *CompensationResponse) UnmarshalJSON(data []byte) error {
+	type plain CompensationResponse
+	return unmarshalLossless(data, (*plain)(r), &r.RawFields)
+}
+
+func (r *CompensationItem) UnmarshalJSON(data []byte) error {
+	type plain CompensationItem
+	return unmarshalLossless(data, (*plain)(r), &r.RawFields)
+}
+
+// ByOrderID groups the settled items by order_id.
+func (r *CompensationResponse) ByOrderID() map[string][]CompensationItem {
+	items := make(map[string][]CompensationItem, len(r.Data))
+	for _, item := range r.Data {
+		items[item.OrderID] = append(items[item.OrderID], item)
+	}
+	return items
+}
+
+// ByPaymentID groups the settled items by payment_id.
+func (r *CompensationResponse) ByPaymentID() map[int64][]CompensationItem {
+	items := make(map[int64][]CompensationItem, len(r.Data))
+	for _, item := range r.Data {
+		items[item.PaymentID.Int64()] = append(items[item.PaymentID.Int64()], item)
+	}
+	return items
+}
+
+// CompensationRegister retrieves the payments settled on the given day.
+func (c client) CompensationRegister(day time.Time) (*CompensationResponse, error) {
+	data := &CompensationRegisterRequest{
+		Action: ActionCompensationRegister,
+		Date:   day.UTC().Format(CompensationDateLayout),
+	}
+	return c.compensation(data)
+}
+
+// CompensationRegisterPeriod retrieves the payments settled between the days from and to.
+func (c client) CompensationRegisterPeriod(from time.Time, to time.Time) (*CompensationResponse, error) {
+	data := &CompensationRegisterPeriodRequest{
+		Action:   ActionCompensationRegisterPeriod,
+		DateFrom: from.UTC().Format(CompensationDateLayout),
+		DateTo:   to.UTC().Format(CompensationDateLayout),
+	}
+	return c.compensation(data)
+}
+
+// PaymentCompensation retrieves the settlement of a single order.
+func (c client) PaymentCompensation(orderID string) (*CompensationResponse, error) {
+	data := &CompensationPerPaymentRequest{Action: ActionCompensationPerPayment, OrderID: orderID}
+	return c.compensation(data)
+}
+
+// compensation sends a compensation register request.
+func (c client) compensation(data any) (*CompensationResponse, error) {
+	req, err := c.prepareServerRequest(data)
+	if err != nil {
+		return nil, err
+	}
+
+	v := &CompensationResponse{}
+	err = c.sendServerRequest(req, v)
+	switch {
+	case err != nil && ErrorRefersToAPI(err):
+		return v, err
+	case err != nil:
+		return nil, err
+	}
+
+	return v, nil
+}
@@ -1,5 +1,13 @@
 package liqpay
 
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
 const (
 	ServerServerURL   = "https://www.liqpay.ua/api/request"
 	ClientServerURL   = "https://www.liqpay.ua/api/3/checkout"
@@ -8,9 +16,25 @@ const (
 
 // Config represents the configuration parameters required for interacting with the LiqPay API.
 type Config struct {
-	PrivateKey string // PrivateKey is the private key used for API authentication.
-	PublicKey  string // PublicKey is the public key used for API authentication.
+	PrivateKey string // PrivateKey is the private key of the default merchant.
+	PublicKey  string // PublicKey is the public key of the default merchant.
 	Debug      bool   // Debug specifies whether debug mode is enabled.
+
+	PreviousKeys []RetiredKey // PreviousKeys are retired private keys of the default merchant, still accepted when verifying signatures.
+
+	Merchants []Merchant // Merchants are additional shops the client can act for, next to the default one.
+
+	ServerURL   string        // ServerURL is the server-server API endpoint. Defaults to ServerServerURL.
+	CheckoutURL string        // CheckoutURL is the client-server checkout endpoint. Defaults to ClientServerURL.
+	Timeout     time.Duration // Timeout limits every HTTP request. Zero means no timeout.
+	Retry       RetryPolicy   // Retry controls retries of informational server-server requests.
+}
+
+// RetryPolicy controls how failed requests are retried.
+// Only requests that do not move money (e.g. status) are retried.
+type RetryPolicy struct {
+	MaxAttempts int           // MaxAttempts is the total number of attempts. Zero or one disables retries.
+	Backoff     time.Duration // Backoff is the delay before the first retry, doubled on every next one.
 }
 
 // NewConfig creates a new Config instance with the provided public key, private key, and debug mode settings.
@@ -21,3 +45,83 @@ func NewConfig(publicKey, privateKey string, debugMode bool) *Config {
 		Debug:      debugMode,
 	}
 }
+
+// Validate checks that the configuration is complete and consistent.
+func (c *Config) Validate() error {
+	var errs []string
+
+	if c.PublicKey == "" {
+		errs = append(errs, "public key is required")
+	}
+	if c.PrivateKey == "" {
+		errs = append(errs, "private key is required")
+	}
+	for i, m := range c.Merchants {
+		if m.PublicKey == "" || m.PrivateKey == "" {
+			errs = append(errs, fmt.Sprintf("merchant %d: public and private keys are required", i))
+		}
+	}
+	for _, u := range []struct{ name, value string }{{"server url", c.ServerURL}, {"checkout url", c.CheckoutURL}} {
+		if u.value == "" {
+			continue
+		}
+		if parsed, err := url.Parse(u.value); err != nil || parsed.Scheme == "" || parsed.Host == "" {
+			errs = append(errs, fmt.Sprintf("%s %q is not an absolute url", u.name, u.value))
+		}
+	}
+	if c.Timeout < 0 {
+		errs = append(errs, "timeout must not be negative")
+	}
+	if c.Retry.MaxAttempts < 0 {
+		errs = append(errs, "retry max attempts must not be negative")
+	}
+	if c.Retry.Backoff < 0 {
+		errs = append(errs, "retry backoff must not be negative")
+	}
+
+	if len(errs) > 0 {
+		return errors.New("liqpay config: " + strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// String returns the configuration with private keys masked.
+func (c Config) String() string {
+	merchants := make([]string, 0, len(c.Merchants))
+	for _, m := range c.Merchants {
+		merchants = append(merchants, m.String())
+	}
+
+	return fmt.Sprintf("Config{PublicKey: %q, PrivateKey: %s, PreviousKeys: %d, Debug: %t, Merchants: [%s], ServerURL: %q, CheckoutURL: %q, Timeout: %s, Retry: {MaxAttempts: %d, Backoff: %s}}",
+		c.PublicKey, maskSecret(c.PrivateKey), len(c.PreviousKeys), c.Debug, strings.Join(merchants, ", "),
+		c.serverURL(), c.checkoutURL(), c.Timeout, c.Retry.MaxAttempts, c.Retry.Backoff)
+}
+
+// GoString returns the configuration with private keys masked, so that %#v does not leak them.
+func (c Config) GoString() string {
+	return c.String()
+}
+
+// serverURL returns the server-server API endpoint.
+func (c Config) serverURL() string {
+	if c.ServerURL != "" {
+		return c.ServerURL
+	}
+	return ServerServerURL
+}
+
+// checkoutURL returns the client-server checkout endpoint.
+func (c Config) checkoutURL() string {
+	if c.CheckoutURL != "" {
+		return c.CheckoutURL
+	}
+	return ClientServerURL
+}
+
+// maskSecret hides a secret value, only telling whether it is set.
+func maskSecret(secret string) string {
+	if secret == "" {
+		return `""`
+	}
+	return `"****"`
+}
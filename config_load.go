@@ -0,0 +1,197 @@
+package liqpay
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Environment variables read by LoadConfigFromEnv.
+const (
+	EnvPublicKey        = "LIQPAY_PUBLIC_KEY"         // Public key of the default merchant
+	EnvPrivateKey       = "LIQPAY_PRIVATE_KEY"        // Private key of the default merchant
+	EnvPrivateKeyFile   = "LIQPAY_PRIVATE_KEY_FILE"   // Path to a file holding the private key of the default merchant
+	EnvDebug            = "LIQPAY_DEBUG"              // Debug mode, parsed with strconv.ParseBool
+	EnvServerURL        = "LIQPAY_SERVER_URL"         // Server-server API endpoint
+	EnvCheckoutURL      = "LIQPAY_CHECKOUT_URL"       // Client-server checkout endpoint
+	EnvTimeout          = "LIQPAY_TIMEOUT"            // HTTP request timeout, parsed with time.ParseDuration
+	EnvRetryMaxAttempts = "LIQPAY_RETRY_MAX_ATTEMPTS" // Total number of attempts of retryable requests
+	EnvRetryBackoff     = "LIQPAY_RETRY_BACKOFF"      // Delay before the first retry, parsed with time.ParseDuration
+)
+
+// fileConfig is the on-disk representation of Config.
+type fileConfig struct {
+	PublicKey      string           `json:"public_key" yaml:"public_key"`
+	PrivateKey     string           `json:"private_key" yaml:"private_key"`
+	PrivateKeyFile string           `json:"private_key_file" yaml:"private_key_file"`
+	PreviousKeys   []fileRetiredKey `json:"previous_keys" yaml:"previous_keys"`
+	Debug          bool             `json:"debug" yaml:"debug"`
+	Merchants      []fileMerchant   `json:"merchants" yaml:"merchants"`
+	ServerURL      string           `json:"server_url" yaml:"server_url"`
+	CheckoutURL    string           `json:"checkout_url" yaml:"checkout_url"`
+	Timeout        string           `json:"timeout" yaml:"timeout"`
+	Retry          struct {
+		MaxAttempts int    `json:"max_attempts" yaml:"max_attempts"`
+		Backoff     string `json:"backoff" yaml:"backoff"`
+	} `json:"retry" yaml:"retry"`
+}
+
+// fileMerchant is the on-disk representation of Merchant.
+type fileMerchant struct {
+	PublicKey      string           `json:"public_key" yaml:"public_key"`
+	PrivateKey     string           `json:"private_key" yaml:"private_key"`
+	PrivateKeyFile string           `json:"private_key_file" yaml:"private_key_file"`
+	PreviousKeys   []fileRetiredKey `json:"previous_keys" yaml:"previous_keys"`
+}
+
+// fileRetiredKey is the on-disk representation of RetiredKey.
+type fileRetiredKey struct {
+	PrivateKey     string `json:"private_key" yaml:"private_key"`
+	PrivateKeyFile string `json:"private_key_file" yaml:"private_key_file"`
+	ExpiresAt      string `json:"expires_at" yaml:"expires_at"` // RFC 3339 time, empty for never
+}
+
+// LoadConfigFromEnv builds a Config from LIQPAY_* environment variables and validates it.
+func LoadConfigFromEnv() (*Config, error) {
+	cfg := &Config{
+		PublicKey:   os.Getenv(EnvPublicKey),
+		ServerURL:   os.Getenv(EnvServerURL),
+		CheckoutURL: os.Getenv(EnvCheckoutURL),
+	}
+
+	var err error
+	if cfg.PrivateKey, err = resolveSecret(os.Getenv(EnvPrivateKey), os.Getenv(EnvPrivateKeyFile)); err != nil {
+		return nil, err
+	}
+	if v := os.Getenv(EnvDebug); v != "" {
+		if cfg.Debug, err = strconv.ParseBool(v); err != nil {
+			return nil, fmt.Errorf("liqpay config: invalid %s: %w", EnvDebug, err)
+		}
+	}
+	if cfg.Timeout, err = parseDuration(EnvTimeout, os.Getenv(EnvTimeout)); err != nil {
+		return nil, err
+	}
+	if v := os.Getenv(EnvRetryMaxAttempts); v != "" {
+		if cfg.Retry.MaxAttempts, err = strconv.Atoi(v); err != nil {
+			return nil, fmt.Errorf("liqpay config: invalid %s: %w", EnvRetryMaxAttempts, err)
+		}
+	}
+	if cfg.Retry.Backoff, err = parseDuration(EnvRetryBackoff, os.Getenv(EnvRetryBackoff)); err != nil {
+		return nil, err
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// LoadConfigFile builds a Config from a YAML (.yaml, .yml) or JSON file and validates it.
+// Private keys may be given inline or as private_key_file paths, e.g. mounted secrets.
+// Retired keys of the default merchant and of every merchant are listed under previous_keys,
+// each with an optional RFC 3339 expires_at.
+func LoadConfigFile(path string) (*Config, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("liqpay config: failed to read config file: %w", err)
+	}
+
+	var fc fileConfig
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(raw, &fc)
+	default:
+		err = json.Unmarshal(raw, &fc)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("liqpay config: failed to parse config file %s: %w", path, err)
+	}
+
+	cfg := &Config{
+		PublicKey:   fc.PublicKey,
+		Debug:       fc.Debug,
+		ServerURL:   fc.ServerURL,
+		CheckoutURL: fc.CheckoutURL,
+	}
+	cfg.Retry.MaxAttempts = fc.Retry.MaxAttempts
+
+	if cfg.PrivateKey, err = resolveSecret(fc.PrivateKey, fc.PrivateKeyFile); err != nil {
+		return nil, err
+	}
+	if cfg.PreviousKeys, err = loadRetiredKeys(fc.PublicKey, fc.PreviousKeys); err != nil {
+		return nil, err
+	}
+	if cfg.Timeout, err = parseDuration("timeout", fc.Timeout); err != nil {
+		return nil, err
+	}
+	if cfg.Retry.Backoff, err = parseDuration("retry backoff", fc.Retry.Backoff); err != nil {
+		return nil, err
+	}
+	for _, m := range fc.Merchants {
+		privateKey, err := resolveSecret(m.PrivateKey, m.PrivateKeyFile)
+		if err != nil {
+			return nil, err
+		}
+		previousKeys, err := loadRetiredKeys(m.PublicKey, m.PreviousKeys)
+		if err != nil {
+			return nil, err
+		}
+		cfg.Merchants = append(cfg.Merchants, Merchant{PublicKey: m.PublicKey, PrivateKey: privateKey, PreviousKeys: previousKeys})
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// loadRetiredKeys converts the previous keys of the merchant with publicKey from their on-disk representation.
+func loadRetiredKeys(publicKey string, keys []fileRetiredKey) ([]RetiredKey, error) {
+	var retired []RetiredKey
+	for _, k := range keys {
+		key := RetiredKey{}
+		var err error
+		if key.PrivateKey, err = resolveSecret(k.PrivateKey, k.PrivateKeyFile); err != nil {
+			return nil, err
+		}
+		if k.ExpiresAt != "" {
+			if key.ExpiresAt, err = time.Parse(time.RFC3339, k.ExpiresAt); err != nil {
+				return nil, fmt.Errorf("liqpay config: invalid expires_at of a previous key of %s: %w", publicKey, err)
+			}
+		}
+		retired = append(retired, key)
+	}
+	return retired, nil
+}
+
+// resolveSecret returns the inline value, or the trimmed content of file if value is empty.
+func resolveSecret(value string, file string) (string, error) {
+	if value != "" || file == "" {
+		return value, nil
+	}
+
+	raw, err := os.ReadFile(file)
+	if err != nil {
+		return "", fmt.Errorf("liqpay config: failed to read secret file: %w", err)
+	}
+	return strings.TrimSpace(string(raw)), nil
+}
+
+// parseDuration parses an optional duration setting.
+func parseDuration(name string, value string) (time.Duration, error) {
+	if value == "" {
+		return 0, nil
+	}
+
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("liqpay config: invalid %s: %w", name, err)
+	}
+	return d, nil
+}
@@ -0,0 +1,79 @@
+package liqpay
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/jim-ww/liqpay-go/signature"
+)
+
+func TestLoadConfigFilePreviousKeys(t *testing.T) {
+	expires := time.Date(2026, 12, 1, 0, 0, 0, 0, time.UTC)
+
+	files := map[string]string{
+		"config.yaml": `
+public_key: pk
+private_key: sk
+previous_keys:
+  - private_key: old
+    expires_at: 2026-12-01T00:00:00Z
+merchants:
+  - public_key: pk2
+    private_key: sk2
+    previous_keys:
+      - private_key: old2
+`,
+		"config.json": `{
+	"public_key": "pk", "private_key": "sk",
+	"previous_keys": [{"private_key": "old", "expires_at": "2026-12-01T00:00:00Z"}],
+	"merchants": [{"public_key": "pk2", "private_key": "sk2", "previous_keys": [{"private_key": "old2"}]}]
+}`,
+	}
+
+	for name, content := range files {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), name)
+			if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+				t.Fatal(err)
+			}
+
+			cfg, err := LoadConfigFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(cfg.PreviousKeys) != 1 || cfg.PreviousKeys[0].PrivateKey != "old" || !cfg.PreviousKeys[0].ExpiresAt.Equal(expires) {
+				t.Errorf("unexpected previous keys of the default merchant: %+v", cfg.PreviousKeys)
+			}
+			if keys := cfg.Merchants[0].PreviousKeys; len(keys) != 1 || keys[0].PrivateKey != "old2" || !keys[0].ExpiresAt.IsZero() {
+				t.Errorf("unexpected previous keys of the merchant: %+v", keys)
+			}
+		})
+	}
+}
+
+func TestLoadConfigFileInvalidExpiry(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	content := "public_key: pk\nprivate_key: sk\nprevious_keys:\n  - private_key: old\n    expires_at: tomorrow\n"
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := LoadConfigFile(path); err == nil {
+		t.Error("expected an error for an invalid expires_at")
+	}
+}
+
+func TestDefaultMerchantPreviousKeys(t *testing.T) {
+	cfg := &Config{PublicKey: "pk", PrivateKey: "sk", PreviousKeys: []RetiredKey{{PrivateKey: "old"}}}
+	client := NewClient(cfg)
+
+	envelope, err := signature.New("old").Seal(map[string]interface{}{"public_key": "pk", "action": "pay", "status": "success"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := client.ValidateCallback(envelope.Data, envelope.Signature); err != nil {
+		t.Errorf("expected a callback signed with a previous key to be accepted, got %v", err)
+	}
+}
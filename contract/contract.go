@@ -0,0 +1,275 @@
+// Package contract provides a conformance suite for liqpay.Client implementations.
+//
+// Wrappers, decorators and fakes built on top of the client can prove that they behave
+// like it by running the suite from a test:
+//
+//	func TestContract(t *testing.T) {
+//		contract.Run(t, func(cfg *liqpay.Config, opts ...liqpay.Option) liqpay.Client {
+//			return mywrapper.New(liqpay.NewClient(cfg, opts...))
+//		})
+//	}
+//
+// The factory must pass the options on, as they point the client at a local fake of the LiqPay API.
+package contract
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/jim-ww/liqpay-go"
+	"github.com/jim-ww/liqpay-go/signature"
+)
+
+const (
+	PublicKey  = "sandbox_i00000000"                                // PublicKey is the public key the suite configures
+	PrivateKey = "sandbox_tPvFSiHv8yBlbO6jZ3CgC0DtGrURdxhOX4AuISTR" // PrivateKey is the private key the suite configures
+
+	checkoutLocation = "https://www.liqpay.ua/api/3/checkout?token=contract"
+)
+
+// Factory creates the client under test from a configuration and options.
+type Factory func(cfg *liqpay.Config, opts ...liqpay.Option) liqpay.Client
+
+// reply is a canned response of the fake API.
+type reply struct {
+	status   int
+	location string
+	body     string
+}
+
+// fakeAPI is a local fake of the LiqPay API that checks every request it receives.
+type fakeAPI struct {
+	t      *testing.T
+	server *httptest.Server
+
+	mu       sync.Mutex
+	payloads []map[string]interface{}
+	reply    reply
+}
+
+func newFakeAPI(t *testing.T, r reply) *fakeAPI {
+	t.Helper()
+
+	api := &fakeAPI{t: t, reply: r}
+	api.server = httptest.NewServer(http.HandlerFunc(api.serveHTTP))
+	t.Cleanup(api.server.Close)
+	return api
+}
+
+func (a *fakeAPI) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		a.t.Errorf("request method = %s, want POST", r.Method)
+	}
+	if err := r.ParseForm(); err != nil {
+		a.t.Errorf("request form: %v", err)
+	}
+
+	data, sig := r.PostForm.Get("data"), r.PostForm.Get("signature")
+	if err := signature.Verify(PrivateKey, data, sig); err != nil {
+		a.t.Errorf("request signature: %v", err)
+	}
+
+	var payload map[string]interface{}
+	if err := signature.Decode(data, &payload); err != nil {
+		a.t.Errorf("request data: %v", err)
+	}
+
+	a.mu.Lock()
+	a.payloads = append(a.payloads, payload)
+	reply := a.reply
+	a.mu.Unlock()
+
+	if reply.location != "" {
+		w.Header().Set("Location", reply.location)
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(reply.status)
+	_, _ = w.Write([]byte(reply.body))
+}
+
+// client creates the client under test pointed at the fake API.
+func (a *fakeAPI) client(newClient Factory) liqpay.Client {
+	cfg := liqpay.NewConfig(PublicKey, PrivateKey, false)
+	return newClient(cfg, liqpay.WithEndpoints(a.server.URL+"/api/request", a.server.URL+"/api/3/checkout"))
+}
+
+// payload returns the only payload the fake API received.
+func (a *fakeAPI) payload() map[string]interface{} {
+	a.t.Helper()
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if len(a.payloads) != 1 {
+		a.t.Fatalf("fake API received %d requests, want 1", len(a.payloads))
+	}
+	return a.payloads[0]
+}
+
+// expectFields checks fields of a request payload.
+func expectFields(t *testing.T, payload map[string]interface{}, want map[string]interface{}) {
+	t.Helper()
+
+	for key, value := range want {
+		if payload[key] != value {
+			t.Errorf("payload %s = %v, want %v", key, payload[key], value)
+		}
+	}
+}
+
+// Run runs the conformance suite against clients created by newClient.
+func Run(t *testing.T, newClient Factory) {
+	t.Run("RequiredKeys", func(t *testing.T) { testRequiredKeys(t, newClient) })
+	t.Run("APIErrorWithResponse", func(t *testing.T) { testAPIErrorWithResponse(t, newClient) })
+	t.Run("CheckoutRedirect", func(t *testing.T) { testCheckoutRedirect(t, newClient) })
+	t.Run("Call", func(t *testing.T) { testCall(t, newClient) })
+	t.Run("CallbackVerification", func(t *testing.T) { testCallbackVerification(t, newClient) })
+}
+
+func testRequiredKeys(t *testing.T, newClient Factory) {
+	api := newFakeAPI(t, reply{
+		status: http.StatusOK,
+		body:   `{"result":"ok","status":"success","action":"pay","order_id":"order-1","payment_id":1234567890,"amount":10}`,
+	})
+
+	resp, err := api.client(newClient).Status("order-1")
+	if err != nil {
+		t.Fatalf("Status: %v", err)
+	}
+	if resp.Status != liqpay.StatusSuccess || resp.OrderID != "order-1" || resp.PaymentID.Int64() != 1234567890 {
+		t.Errorf("Status = %+v, want the decoded response", resp)
+	}
+
+	expectFields(t, api.payload(), map[string]interface{}{
+		"action":     string(liqpay.ActionStatus),
+		"order_id":   "order-1",
+		"public_key": PublicKey,
+		"version":    liqpay.CurrentAPIVersion,
+	})
+}
+
+func testAPIErrorWithResponse(t *testing.T, newClient Factory) {
+	api := newFakeAPI(t, reply{
+		status: http.StatusOK,
+		body:   `{"result":"error","status":"error","err_code":"err_access","err_description":"Access error","order_id":"order-2"}`,
+	})
+
+	resp, err := api.client(newClient).UpdateSubscription(&liqpay.EditSubscriptionRequest{
+		Amount:      10,
+		Currency:    string(liqpay.CurrencyUAH),
+		Description: "contract",
+		OrderID:     "order-2",
+	})
+
+	var apiErr *liqpay.APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("UpdateSubscription error = %v, want *liqpay.APIError", err)
+	}
+	if apiErr.Code != string(liqpay.NonFinancialAccessError) {
+		t.Errorf("APIError.Code = %q, want %q", apiErr.Code, liqpay.NonFinancialAccessError)
+	}
+	if resp == nil || resp.OrderID != "order-2" {
+		t.Errorf("UpdateSubscription response = %+v, want it filled in alongside the error", resp)
+	}
+
+	expectFields(t, api.payload(), map[string]interface{}{
+		"action":     string(liqpay.ActionSubscribeUpdate),
+		"order_id":   "order-2",
+		"public_key": PublicKey,
+		"version":    liqpay.CurrentAPIVersion,
+	})
+}
+
+func testCheckoutRedirect(t *testing.T, newClient Factory) {
+	api := newFakeAPI(t, reply{status: http.StatusFound, location: checkoutLocation})
+
+	link, err := api.client(newClient).CreateCheckout(&liqpay.CheckoutRequest{
+		Amount:      10,
+		Currency:    liqpay.CurrencyUAH,
+		Description: "contract",
+		OrderID:     "order-3",
+	})
+	if err != nil {
+		t.Fatalf("CreateCheckout: %v", err)
+	}
+	if link != checkoutLocation {
+		t.Errorf("CreateCheckout = %q, want %q", link, checkoutLocation)
+	}
+
+	expectFields(t, api.payload(), map[string]interface{}{
+		"action":     string(liqpay.ActionPay),
+		"order_id":   "order-3",
+		"public_key": PublicKey,
+		"version":    liqpay.CurrentAPIVersion,
+	})
+}
+
+func testCall(t *testing.T, newClient Factory) {
+	api := newFakeAPI(t, reply{status: http.StatusOK, body: `{"result":"ok","custom":"value"}`})
+
+	var resp struct {
+		Result string `json:"result"`
+		Custom string `json:"custom"`
+	}
+	if err := api.client(newClient).Call("custom_action", map[string]interface{}{"order_id": "order-4"}, &resp); err != nil {
+		t.Fatalf("Call: %v", err)
+	}
+	if resp.Custom != "value" {
+		t.Errorf("Call response custom = %q, want %q", resp.Custom, "value")
+	}
+
+	expectFields(t, api.payload(), map[string]interface{}{
+		"action":     "custom_action",
+		"order_id":   "order-4",
+		"public_key": PublicKey,
+		"version":    liqpay.CurrentAPIVersion,
+	})
+}
+
+func testCallbackVerification(t *testing.T, newClient Factory) {
+	api := newFakeAPI(t, reply{status: http.StatusOK, body: `{}`})
+	c := api.client(newClient)
+
+	payload, err := json.Marshal(map[string]interface{}{
+		"action":     "pay",
+		"amount":     100.5,
+		"currency":   "UAH",
+		"order_id":   "order-5",
+		"payment_id": 1234567890,
+		"public_key": PublicKey,
+		"status":     "success",
+		"version":    3,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := signature.Encode(json.RawMessage(payload))
+	if err != nil {
+		t.Fatal(err)
+	}
+	sig := signature.Sign(PrivateKey, data)
+
+	if err := c.ValidateCallback(data, sig); err != nil {
+		t.Errorf("ValidateCallback: %v", err)
+	}
+
+	callback, err := c.DecodeCallback(data, sig)
+	if err != nil {
+		t.Fatalf("DecodeCallback: %v", err)
+	}
+	if callback.OrderID != "order-5" || callback.Status != string(liqpay.StatusSuccess) || callback.Amount.Float64() != 100.5 {
+		t.Errorf("DecodeCallback = %+v, want the decoded callback", callback)
+	}
+
+	forged := signature.Sign("wrong"+PrivateKey, data)
+	if err := c.ValidateCallback(data, forged); !errors.Is(err, signature.ErrInvalidSignature) {
+		t.Errorf("ValidateCallback with a forged signature = %v, want signature.ErrInvalidSignature", err)
+	}
+	if _, err := c.DecodeCallback(data, forged); err == nil {
+		t.Error("DecodeCallback with a forged signature succeeded")
+	}
+}
@@ -0,0 +1,14 @@
+package liqpay_test
+
+import (
+	"testing"
+
+	"github.com/jim-ww/liqpay-go"
+	"github.com/jim-ww/liqpay-go/contract"
+)
+
+func TestContract(t *testing.T) {
+	contract.Run(t, func(cfg *liqpay.Config, opts ...liqpay.Option) liqpay.Client {
+		return liqpay.NewClient(cfg, opts...)
+	})
+}
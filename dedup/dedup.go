@@ -0,0 +1,168 @@
+// Package dedup protects the callback path against repeated deliveries and replayed callbacks.
+//
+// LiqPay may deliver a callback several times, and a captured data/signature pair stays valid
+// forever. A Guard verifies callbacks, rejects those it has already seen within a TTL and,
+// optionally, those whose dates are implausibly old. Choose a TTL longer than the maximum age,
+// so that every replay is caught by one of the checks.
+package dedup
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/jim-ww/liqpay-go"
+)
+
+var (
+	// ErrDuplicate is returned for a callback that was already seen.
+	ErrDuplicate = errors.New("liqpay dedup: callback already processed")
+	// ErrStale is returned for a callback whose dates are older than the maximum age.
+	ErrStale = errors.New("liqpay dedup: callback is too old")
+)
+
+// DefaultTTL is how long callbacks are remembered by default.
+const DefaultTTL = 72 * time.Hour
+
+// Store remembers the keys of seen callbacks. Implementations must be safe for concurrent use.
+type Store interface {
+	// Seen records key for ttl and reports whether it was already recorded and has not expired.
+	// The check and the write must be atomic.
+	Seen(key string, ttl time.Duration) (bool, error)
+	// Forget removes key, so that the callback is accepted again.
+	Forget(key string) error
+}
+
+// Key returns the deduplication key of a callback, made of payment_id, status and end_date.
+func Key(cb *liqpay.Callback) string {
+	return fmt.Sprintf("%d:%s:%d", cb.PaymentID.Int64(), cb.Status, cb.EndDate.Milliseconds())
+}
+
+// Option configures a Guard.
+type Option func(*Guard)
+
+// WithTTL sets how long callbacks are remembered.
+func WithTTL(ttl time.Duration) Option {
+	return func(g *Guard) {
+		g.ttl = ttl
+	}
+}
+
+// WithMaxAge rejects callbacks whose end_date, or create_date if it is not set, is older than maxAge.
+// Callbacks carrying neither date are rejected too. By default the age is not checked.
+func WithMaxAge(maxAge time.Duration) Option {
+	return func(g *Guard) {
+		g.maxAge = maxAge
+	}
+}
+
+// WithNow sets the clock used for the age check.
+func WithNow(now func() time.Time) Option {
+	return func(g *Guard) {
+		g.now = now
+	}
+}
+
+// Guard verifies callbacks and rejects duplicates and replays.
+type Guard struct {
+	verifier liqpay.CallbackVerifier
+	store    Store
+	ttl      time.Duration
+	maxAge   time.Duration
+	now      func() time.Time
+}
+
+// New creates a Guard that verifies callbacks with v and remembers them in store.
+func New(v liqpay.CallbackVerifier, store Store, opts ...Option) *Guard {
+	g := &Guard{verifier: v, store: store, ttl: DefaultTTL, now: time.Now}
+	for _, opt := range opts {
+		opt(g)
+	}
+	return g
+}
+
+// DecodeCallback verifies and decodes a callback, then checks it with Check.
+// The callback is returned alongside ErrDuplicate and ErrStale, so that the handler can
+// still acknowledge it.
+func (g *Guard) DecodeCallback(data string, signature string) (*liqpay.Callback, error) {
+	cb, err := g.verifier.DecodeCallback(data, signature)
+	if err != nil {
+		return nil, err
+	}
+	return cb, g.Check(cb)
+}
+
+// Check rejects a verified callback that is too old or was already seen, and remembers it otherwise.
+func (g *Guard) Check(cb *liqpay.Callback) error {
+	if g.maxAge > 0 {
+		date := cb.EndDate.Time
+		if date.IsZero() {
+			date = cb.CreateDate.Time
+		}
+		if date.IsZero() || g.now().Sub(date) > g.maxAge {
+			return ErrStale
+		}
+	}
+
+	seen, err := g.store.Seen(Key(cb), g.ttl)
+	if err != nil {
+		return fmt.Errorf("liqpay dedup: failed to check callback: %w", err)
+	}
+	if seen {
+		return ErrDuplicate
+	}
+	return nil
+}
+
+// Forget makes the guard accept the callback again, e.g. after its processing failed
+// and LiqPay should be able to deliver it once more.
+func (g *Guard) Forget(cb *liqpay.Callback) error {
+	if err := g.store.Forget(Key(cb)); err != nil {
+		return fmt.Errorf("liqpay dedup: failed to forget callback: %w", err)
+	}
+	return nil
+}
+
+// MemoryStore remembers callback keys in memory, sweeping expired ones at most once a minute.
+// Each process has its own, so replicas behind a load balancer need a shared Store instead.
+type MemoryStore struct {
+	mu      sync.Mutex
+	expires map[string]time.Time
+	now     func() time.Time
+	sweep   time.Time
+}
+
+// NewMemoryStore creates an empty in-memory store.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{expires: make(map[string]time.Time), now: time.Now}
+}
+
+func (s *MemoryStore) Seen(key string, ttl time.Duration) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	if now.After(s.sweep) {
+		for k, expires := range s.expires {
+			if !now.Before(expires) {
+				delete(s.expires, k)
+			}
+		}
+		s.sweep = now.Add(time.Minute)
+	}
+
+	if expires, ok := s.expires[key]; ok && now.Before(expires) {
+		return true, nil
+	}
+	s.expires[key] = now.Add(ttl)
+	return false, nil
+}
+
+func (s *MemoryStore) Forget(key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.expires, key)
+	return nil
+}
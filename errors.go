@@ -1,6 +1,11 @@
 package liqpay
 
-import "fmt"
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+)
 
 type AntiFraudError string
 
@@ -119,19 +124,71 @@ const (
 
 // APIError represents an error returned by the LiqPay API.
 type APIError struct {
-	Status string `json:"status"`
-	Code   string `json:"err_code"`
-	Desc   string `json:"err_description"`
+	Status string          `json:"status"`
+	Code   string          `json:"err_code"`
+	Erc    string          `json:"err_erc"`
+	Desc   string          `json:"err_description"`
+	Raw    json.RawMessage `json:"-"` // Raw is the whole response body
 }
 
 func (e APIError) Error() string {
 	return fmt.Sprintf("status: %s, code: %s, description: %s", e.Status, e.Code, e.Desc)
 }
 
+// FinancialCode returns the error code as a FinancialError if it is numeric.
+func (e APIError) FinancialCode() (FinancialError, bool) {
+	code, err := strconv.Atoi(e.Code)
+	if err != nil {
+		return 0, false
+	}
+	return FinancialError(code), true
+}
+
+// newAPIError builds an APIError from a decoded error response, accepting string or numeric codes.
+func newAPIError(res map[string]interface{}, raw []byte) *APIError {
+	status := stringField(res, "status")
+	if status == "" {
+		status = stringField(res, "result")
+	}
+
+	return &APIError{
+		Status: status,
+		Code:   stringField(res, "err_code"),
+		Erc:    stringField(res, "err_erc"),
+		Desc:   stringField(res, "err_description"),
+		Raw:    append(json.RawMessage(nil), raw...),
+	}
+}
+
+// stringField returns a string or number field of a decoded response as a string.
+func stringField(res map[string]interface{}, key string) string {
+	switch v := res[key].(type) {
+	case string:
+		return v
+	case json.Number:
+		return v.String()
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	}
+	return ""
+}
+
+// HTTPError is returned when LiqPay responds with an unexpected HTTP status code.
+type HTTPError struct {
+	StatusCode int    // StatusCode is the HTTP status code of the response
+	Body       []byte // Body is the response body
+}
+
+func (e HTTPError) Error() string {
+	return fmt.Sprintf("liqpay client: unexpected status code: %d", e.StatusCode)
+}
+
 // ConvertToAPIError converts an error to *APIError type if possible.
 func ConvertToAPIError(err error) (*APIError, error) {
-	apiErr, ok := err.(*APIError)
-	if !ok {
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
 		return nil, fmt.Errorf("failed to convert error to APIError: %w", err)
 	}
 	return apiErr, nil
@@ -139,8 +196,32 @@ func ConvertToAPIError(err error) (*APIError, error) {
 
 // ErrorRefersToAPI checks if the error refers to an APIError.
 func ErrorRefersToAPI(err error) bool {
-	if _, ok := err.(*APIError); ok {
-		return true
-	}
-	return false
+	var apiErr *APIError
+	return errors.As(err, &apiErr)
+}
+
+// ErrNotSent is matched by errors raised before a request was sent, e.g. for an unknown merchant
+// or a payload that could not be encoded. A request failing with such an error did not reach LiqPay.
+var ErrNotSent = errors.New("liqpay client: request was not sent")
+
+// notSentError marks an error raised before a request was sent, keeping its message and chain.
+type notSentError struct {
+	err error
+}
+
+func (e *notSentError) Error() string {
+	return e.err.Error()
+}
+
+func (e *notSentError) Unwrap() error {
+	return e.err
+}
+
+func (e *notSentError) Is(target error) bool {
+	return target == ErrNotSent
+}
+
+// notSent marks err as raised before the request was sent.
+func notSent(err error) error {
+	return &notSentError{err: err}
 }
@@ -0,0 +1,275 @@
+// Package idempotency makes money-moving LiqPay calls safe to retry.
+//
+// Every request carries a caller-chosen key. The intent is stored before the request is sent
+// and the outcome after it, so a repeated request with the same key gets the stored result
+// instead of moving money again. When the outcome of a request is unknown, e.g. after a timeout,
+// the payment status is checked before the request is sent again.
+package idempotency
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jim-ww/liqpay-go"
+)
+
+var (
+	// ErrKeyReused is returned when a key is reused for a different request.
+	ErrKeyReused = errors.New("liqpay idempotency: key was used for a different request")
+	// ErrInProgress is returned when a request with the same key is still in flight.
+	ErrInProgress = errors.New("liqpay idempotency: request with the same key is in progress")
+	// ErrOutcomeUnknown is returned when a request failed and the payment status could not tell whether it landed.
+	// The request is retried safely by calling again with the same key.
+	ErrOutcomeUnknown = errors.New("liqpay idempotency: outcome of the request is unknown")
+)
+
+// RefundClient is the part of liqpay.Client the Refunder uses.
+type RefundClient interface {
+	liqpay.RefundClient
+	liqpay.StatusClient
+}
+
+// DefaultLease is how long a request is considered in flight by default.
+const DefaultLease = time.Minute
+
+// Option configures a Refunder.
+type Option func(*Refunder)
+
+// WithLease sets how long a pending request is considered in flight. A pending request older
+// than the lease, e.g. of a crashed process, is treated as one with an unknown outcome.
+// The lease must be longer than the client timeout.
+func WithLease(lease time.Duration) Option {
+	return func(r *Refunder) {
+		r.lease = lease
+	}
+}
+
+// Refunder sends refunds at most once per idempotency key.
+type Refunder struct {
+	client RefundClient
+	store  Store
+	lease  time.Duration
+	now    func() time.Time
+}
+
+// NewRefunder creates a Refunder that sends refunds with c and keeps records in store.
+func NewRefunder(c RefundClient, store Store, opts ...Option) *Refunder {
+	r := &Refunder{client: c, store: store, lease: DefaultLease, now: time.Now}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+// Refund refunds amount of the order once per key. A repeated call with the same key returns
+// the stored response and error. A call for a key whose earlier request has an unknown outcome
+// checks the payment status first, and only sends the refund again if it did not land.
+// An invalid request, e.g. with an amount that is not positive, is rejected before any intent is recorded,
+// and a request that failed before it was sent, e.g. for an unknown merchant, is sent again by the next call.
+func (r *Refunder) Refund(key string, orderID string, amount string) (*liqpay.RefundResponse, error) {
+	req := &liqpay.RefundRequest{Action: liqpay.ActionRefund, OrderID: orderID, Amount: amount}
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	rec, ok, err := r.store.Get(key)
+	if err != nil {
+		return nil, fmt.Errorf("liqpay idempotency: failed to read record: %w", err)
+	}
+
+	if !ok {
+		status, err := r.client.Status(orderID)
+		if err != nil {
+			return nil, err
+		}
+
+		now := r.now()
+		rec = &Record{
+			Key:            key,
+			Action:         liqpay.ActionRefund,
+			OrderID:        orderID,
+			Amount:         amount,
+			RefundedBefore: refunded(status),
+			State:          StatePending,
+			CreatedAt:      now,
+			UpdatedAt:      now,
+		}
+
+		created, err := r.store.Create(rec)
+		if err != nil {
+			return nil, fmt.Errorf("liqpay idempotency: failed to record intent: %w", err)
+		}
+		if created {
+			return r.send(rec)
+		}
+
+		if rec, ok, err = r.store.Get(key); err != nil {
+			return nil, fmt.Errorf("liqpay idempotency: failed to read record: %w", err)
+		}
+		if !ok {
+			return nil, ErrInProgress
+		}
+	}
+
+	if rec.Action != liqpay.ActionRefund || rec.OrderID != orderID || rec.Amount != amount {
+		return nil, ErrKeyReused
+	}
+
+	switch rec.State {
+	case StateSucceeded:
+		return decodeResponse(rec)
+	case StateFailed:
+		resp, err := decodeResponse(rec)
+		if err != nil {
+			return nil, err
+		}
+		return resp, rec.Error
+	case StateNotSent:
+		status, err := r.client.Status(orderID)
+		if err != nil {
+			return nil, err
+		}
+		rec.RefundedBefore = refunded(status)
+		return r.send(rec)
+	case StatePending:
+		if r.now().Sub(rec.UpdatedAt) < r.lease {
+			return nil, ErrInProgress
+		}
+	}
+
+	landed, resp, err := r.check(rec)
+	if err != nil {
+		return nil, err
+	}
+	if landed {
+		return resp, nil
+	}
+	return r.send(rec)
+}
+
+// send sends the refund of a record and stores the outcome.
+func (r *Refunder) send(rec *Record) (*liqpay.RefundResponse, error) {
+	if rec.State != StatePending {
+		rec.State = StatePending
+		rec.UpdatedAt = r.now()
+		if err := r.store.Update(rec); err != nil {
+			return nil, fmt.Errorf("liqpay idempotency: failed to record intent: %w", err)
+		}
+	}
+
+	resp, err := r.client.Refund(rec.OrderID, rec.Amount)
+
+	var apiErr *liqpay.APIError
+	switch {
+	case err == nil:
+		return resp, r.finish(rec, StateSucceeded, resp, nil)
+	case errors.As(err, &apiErr):
+		if ferr := r.finish(rec, StateFailed, resp, apiErr); ferr != nil {
+			return nil, ferr
+		}
+		return resp, err
+	case !sent(err):
+		rec.State = StateNotSent
+		rec.UpdatedAt = r.now()
+		if uerr := r.store.Update(rec); uerr != nil {
+			return nil, fmt.Errorf("liqpay idempotency: failed to record outcome: %w", uerr)
+		}
+		return nil, err
+	}
+
+	landed, checked, cerr := r.check(rec)
+	if cerr == nil && landed {
+		return checked, nil
+	}
+
+	rec.State = StateUnknown
+	rec.UpdatedAt = r.now()
+	if uerr := r.store.Update(rec); uerr != nil {
+		return nil, fmt.Errorf("liqpay idempotency: failed to record outcome: %w", uerr)
+	}
+	return nil, fmt.Errorf("%w: %v", ErrOutcomeUnknown, err)
+}
+
+// check asks LiqPay whether the refund of a record landed, and records it if so.
+func (r *Refunder) check(rec *Record) (bool, *liqpay.RefundResponse, error) {
+	status, err := r.client.Status(rec.OrderID)
+	if err != nil {
+		return false, nil, fmt.Errorf("%w: status check failed: %v", ErrOutcomeUnknown, err)
+	}
+
+	amount, err := strconv.ParseFloat(strings.TrimSpace(rec.Amount), 64)
+	if err != nil || !(amount > 0) {
+		return false, nil, fmt.Errorf("liqpay idempotency: invalid amount %q", rec.Amount)
+	}
+	if liqpay.ToKopecks(refunded(status)-rec.RefundedBefore) < liqpay.ToKopecks(amount) {
+		return false, nil, nil
+	}
+
+	resp := &liqpay.RefundResponse{
+		Action:    liqpay.ActionRefund,
+		PaymentID: status.PaymentID,
+		Status:    status.Status.String(),
+	}
+	return true, resp, r.finish(rec, StateSucceeded, resp, nil)
+}
+
+// finish stores the outcome of a request.
+func (r *Refunder) finish(rec *Record, state State, resp *liqpay.RefundResponse, apiErr *liqpay.APIError) error {
+	rec.State = state
+	rec.Error = apiErr
+	rec.UpdatedAt = r.now()
+	rec.Response = nil
+	if resp != nil {
+		raw := resp.RawJSON()
+		if raw == nil {
+			var err error
+			if raw, err = json.Marshal(resp); err != nil {
+				return fmt.Errorf("liqpay idempotency: failed to encode response: %w", err)
+			}
+		}
+		rec.Response = raw
+	}
+
+	if err := r.store.Update(rec); err != nil {
+		return fmt.Errorf("liqpay idempotency: failed to record outcome: %w", err)
+	}
+	return nil
+}
+
+// decodeResponse decodes the stored response of a record.
+func decodeResponse(rec *Record) (*liqpay.RefundResponse, error) {
+	if rec.Response == nil {
+		return nil, nil
+	}
+
+	resp := &liqpay.RefundResponse{}
+	if err := json.Unmarshal(rec.Response, resp); err != nil {
+		return nil, fmt.Errorf("liqpay idempotency: failed to decode stored response: %w", err)
+	}
+	return resp, nil
+}
+
+// sent reports whether a request failing with err may have reached LiqPay. Requests rejected by
+// validation, for an unknown merchant or otherwise before sending certainly did not.
+func sent(err error) bool {
+	var validationErr *liqpay.ValidationError
+	switch {
+	case errors.As(err, &validationErr),
+		errors.Is(err, liqpay.ErrUnknownMerchant),
+		errors.Is(err, liqpay.ErrNotSent):
+		return false
+	}
+	return true
+}
+
+// refunded returns the amount refunded for a payment.
+func refunded(status *liqpay.StatusResponse) float64 {
+	if status.Status == liqpay.StatusReversed && status.RefundAmount == 0 {
+		return status.Amount.Float64()
+	}
+	return status.RefundAmount.Float64()
+}
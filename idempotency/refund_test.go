@@ -0,0 +1,147 @@
+package idempotency
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/jim-ww/liqpay-go"
+	"github.com/jim-ww/liqpay-go/mock"
+)
+
+func newStatusMock() *mock.Client {
+	m := mock.New()
+	m.StatusFunc = func(orderID string) (*liqpay.StatusResponse, error) {
+		return &liqpay.StatusResponse{
+			OrderID: orderID,
+			Status:  liqpay.StatusSuccess,
+			Amount:  liqpay.FlexFloat(100),
+		}, nil
+	}
+	return m
+}
+
+func TestRefundRejectsInvalidAmount(t *testing.T) {
+	for _, amount := range []string{"-5", "0", "abc"} {
+		t.Run(amount, func(t *testing.T) {
+			m := newStatusMock()
+			store := NewMemoryStore()
+
+			_, err := NewRefunder(m, store).Refund("key", "order-1", amount)
+
+			var validationErr *liqpay.ValidationError
+			if !errors.As(err, &validationErr) {
+				t.Fatalf("expected *liqpay.ValidationError, got %v", err)
+			}
+			if calls := m.Calls(); len(calls) != 0 {
+				t.Errorf("expected no calls, got %v", calls)
+			}
+			if _, ok, _ := store.Get("key"); ok {
+				t.Error("expected no record")
+			}
+		})
+	}
+}
+
+func TestRefundPreSendErrorIsRetryable(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+	}{
+		{"validation", &liqpay.ValidationError{Fields: map[string][]string{"order_id": {"is required"}}}},
+		{"unknown merchant", fmt.Errorf("%w: public_key", liqpay.ErrUnknownMerchant)},
+		{"not sent", fmt.Errorf("%w: failed to encode payload", liqpay.ErrNotSent)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fail := true
+			m := newStatusMock()
+			m.RefundFunc = func(orderID string, amount string) (*liqpay.RefundResponse, error) {
+				if fail {
+					return nil, tt.err
+				}
+				return &liqpay.RefundResponse{Action: liqpay.ActionRefund, Status: string(liqpay.StatusReversed)}, nil
+			}
+			store := NewMemoryStore()
+			r := NewRefunder(m, store)
+
+			if _, err := r.Refund("key", "order-1", "10"); err != tt.err {
+				t.Fatalf("expected %v, got %v", tt.err, err)
+			}
+			if n := len(m.CallsTo("Status")); n != 1 {
+				t.Errorf("expected only the baseline status check, got %d", n)
+			}
+			if rec, _, _ := store.Get("key"); rec.State != StateNotSent {
+				t.Errorf("expected state %s, got %s", StateNotSent, rec.State)
+			}
+
+			fail = false
+			resp, err := r.Refund("key", "order-1", "10")
+			if err != nil || resp == nil {
+				t.Fatalf("expected the refund to be sent again, got %v", err)
+			}
+			if n := len(m.CallsTo("Refund")); n != 2 {
+				t.Errorf("expected the refund to be sent twice, got %d", n)
+			}
+			if rec, _, _ := store.Get("key"); rec.State != StateSucceeded {
+				t.Errorf("expected state %s, got %s", StateSucceeded, rec.State)
+			}
+
+			if _, err := r.Refund("key", "order-1", "10"); err != nil {
+				t.Fatal(err)
+			}
+			if n := len(m.CallsTo("Refund")); n != 2 {
+				t.Errorf("expected the succeeded refund not to be sent again, got %d calls", n)
+			}
+		})
+	}
+}
+
+func TestRefundTimeout(t *testing.T) {
+	tests := []struct {
+		name         string
+		refundAmount float64
+		wantErr      error
+		wantState    State
+	}{
+		{"landed", 10, nil, StateSucceeded},
+		{"not landed", 0, ErrOutcomeUnknown, StateUnknown},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			refunded := 0.0
+			m := mock.New()
+			m.StatusFunc = func(orderID string) (*liqpay.StatusResponse, error) {
+				return &liqpay.StatusResponse{OrderID: orderID, Status: liqpay.StatusSuccess, RefundAmount: liqpay.FlexFloat(refunded)}, nil
+			}
+			m.RefundFunc = func(orderID string, amount string) (*liqpay.RefundResponse, error) {
+				refunded = tt.refundAmount
+				return nil, errors.New("liqpay client: request failed: timeout")
+			}
+			store := NewMemoryStore()
+
+			_, err := NewRefunder(m, store).Refund("key", "order-1", "10")
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected %v, got %v", tt.wantErr, err)
+			}
+
+			rec, _, _ := store.Get("key")
+			if rec.State != tt.wantState {
+				t.Errorf("expected state %s, got %s", tt.wantState, rec.State)
+			}
+		})
+	}
+}
+
+func TestCheckRequiresPositiveAmount(t *testing.T) {
+	r := NewRefunder(newStatusMock(), NewMemoryStore())
+
+	for _, amount := range []string{"-5", "0"} {
+		landed, _, err := r.check(&Record{Key: "key", OrderID: "order-1", Amount: amount})
+		if err == nil || landed {
+			t.Errorf("amount %s: expected an error, got landed=%t err=%v", amount, landed, err)
+		}
+	}
+}
@@ -0,0 +1,87 @@
+package idempotency
+
+import (
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/jim-ww/liqpay-go"
+)
+
+// State is the state of an idempotent request.
+type State string
+
+const (
+	StatePending   State = "pending"   // Intent recorded, the request is in flight
+	StateUnknown   State = "unknown"   // The request failed and it is not known whether it landed
+	StateSucceeded State = "succeeded" // LiqPay accepted the request
+	StateFailed    State = "failed"    // LiqPay rejected the request
+	StateNotSent   State = "not_sent"  // The request failed before it was sent, the next call sends it again
+)
+
+// Record is the stored intent and outcome of an idempotent request.
+type Record struct {
+	Key            string           `json:"key"`             // Idempotency key chosen by the caller
+	Action         liqpay.Action    `json:"action"`          // Action of the request
+	OrderID        string           `json:"order_id"`        // Order the request is for
+	Amount         string           `json:"amount"`          // Amount of the request
+	RefundedBefore float64          `json:"refunded_before"` // Amount refunded for the order before the request
+	State          State            `json:"state"`           // Current state
+	Response       json.RawMessage  `json:"response"`        // Response of a finished request
+	Error          *liqpay.APIError `json:"error"`           // Error LiqPay rejected the request with
+	CreatedAt      time.Time        `json:"created_at"`      // When the intent was recorded
+	UpdatedAt      time.Time        `json:"updated_at"`      // When the record changed last
+}
+
+// Store persists idempotency records. Implementations must be safe for concurrent use.
+type Store interface {
+	// Get returns the record stored under key, or false if there is none.
+	Get(key string) (*Record, bool, error)
+	// Create stores a new record, or returns false if a record with the same key exists.
+	// The check and the write must be atomic.
+	Create(rec *Record) (bool, error)
+	// Update replaces the stored record with the same key.
+	Update(rec *Record) error
+}
+
+// MemoryStore keeps records in memory. They are lost on restart, after which a key is treated
+// as new, so use a persistent Store where refunds may be retried across restarts.
+type MemoryStore struct {
+	mu      sync.Mutex
+	records map[string]Record
+}
+
+// NewMemoryStore creates an empty in-memory store.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{records: make(map[string]Record)}
+}
+
+func (s *MemoryStore) Get(key string) (*Record, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	rec, ok := s.records[key]
+	if !ok {
+		return nil, false, nil
+	}
+	return &rec, true, nil
+}
+
+func (s *MemoryStore) Create(rec *Record) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.records[rec.Key]; ok {
+		return false, nil
+	}
+	s.records[rec.Key] = *rec
+	return true, nil
+}
+
+func (s *MemoryStore) Update(rec *Record) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.records[rec.Key] = *rec
+	return nil
+}
@@ -0,0 +1,206 @@
+package liqpay
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/jim-ww/liqpay-go/signature"
+)
+
+// ErrUnknownMerchant is returned when no merchant is registered for a public key.
+var ErrUnknownMerchant = errors.New("liqpay client: unknown merchant")
+
+// Merchant holds the keys of a single LiqPay shop.
+type Merchant struct {
+	PublicKey    string       `json:"public_key"`              // PublicKey is the shop public key.
+	PrivateKey   string       `json:"private_key"`             // PrivateKey is the primary private key, used for signing.
+	PreviousKeys []RetiredKey `json:"previous_keys,omitempty"` // PreviousKeys are still accepted when verifying signatures.
+}
+
+// String returns the merchant with private keys masked.
+func (m Merchant) String() string {
+	return fmt.Sprintf("Merchant{PublicKey: %q, PrivateKey: %s, PreviousKeys: %d}",
+		m.PublicKey, maskSecret(m.PrivateKey), len(m.PreviousKeys))
+}
+
+// GoString returns the merchant with private keys masked, so that %#v does not leak them.
+func (m Merchant) GoString() string {
+	return m.String()
+}
+
+// RetiredKey is a former private key that is accepted for verification until it expires.
+type RetiredKey struct {
+	PrivateKey string    `json:"private_key"`          // PrivateKey is the retired private key.
+	ExpiresAt  time.Time `json:"expires_at,omitempty"` // ExpiresAt is when the key stops being accepted. Zero means never.
+}
+
+// valid reports whether the key is still accepted at the given time.
+func (k RetiredKey) valid(now time.Time) bool {
+	return k.ExpiresAt.IsZero() || now.Before(k.ExpiresAt)
+}
+
+// MerchantRegistry is a set of merchants keyed by public key. It is safe for concurrent use,
+// so merchants can be added and their keys rotated while the client is serving requests.
+type MerchantRegistry struct {
+	mu        sync.RWMutex
+	merchants map[string]Merchant
+	now       func() time.Time
+}
+
+// NewMerchantRegistry creates a registry holding the given merchants.
+func NewMerchantRegistry(merchants ...Merchant) (*MerchantRegistry, error) {
+	r := newMerchantRegistry(len(merchants))
+	for _, m := range merchants {
+		if err := r.Add(m); err != nil {
+			return nil, err
+		}
+	}
+	return r, nil
+}
+
+func newMerchantRegistry(size int) *MerchantRegistry {
+	return &MerchantRegistry{
+		merchants: make(map[string]Merchant, size),
+		now:       time.Now,
+	}
+}
+
+// Add registers a merchant, replacing any merchant with the same public key.
+func (r *MerchantRegistry) Add(m Merchant) error {
+	if m.PublicKey == "" || m.PrivateKey == "" {
+		return errors.New("liqpay client: merchant public and private keys are required")
+	}
+
+	m.PreviousKeys = append([]RetiredKey(nil), m.PreviousKeys...)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.merchants[m.PublicKey] = m
+	return nil
+}
+
+// Rotate makes privateKey the primary key of the merchant. The former primary key
+// keeps being accepted for verification during grace; a non-positive grace drops it at once.
+func (r *MerchantRegistry) Rotate(publicKey string, privateKey string, grace time.Duration) error {
+	if privateKey == "" {
+		return errors.New("liqpay client: merchant private key is required")
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	m, ok := r.merchants[publicKey]
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrUnknownMerchant, publicKey)
+	}
+	if m.PrivateKey == privateKey {
+		return nil
+	}
+
+	now := r.now()
+	keys := make([]RetiredKey, 0, len(m.PreviousKeys)+1)
+	if grace > 0 {
+		keys = append(keys, RetiredKey{PrivateKey: m.PrivateKey, ExpiresAt: now.Add(grace)})
+	}
+	for _, k := range m.PreviousKeys {
+		if k.valid(now) && k.PrivateKey != privateKey {
+			keys = append(keys, k)
+		}
+	}
+
+	m.PrivateKey = privateKey
+	m.PreviousKeys = keys
+	r.merchants[publicKey] = m
+	return nil
+}
+
+// Retire stops accepting a previous private key of the merchant before it expires.
+func (r *MerchantRegistry) Retire(publicKey string, privateKey string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	m, ok := r.merchants[publicKey]
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrUnknownMerchant, publicKey)
+	}
+	if m.PrivateKey == privateKey {
+		return errors.New("liqpay client: cannot retire the primary private key, rotate it first")
+	}
+
+	keys := make([]RetiredKey, 0, len(m.PreviousKeys))
+	for _, k := range m.PreviousKeys {
+		if k.PrivateKey != privateKey {
+			keys = append(keys, k)
+		}
+	}
+	m.PreviousKeys = keys
+	r.merchants[publicKey] = m
+	return nil
+}
+
+// Remove unregisters the merchant with the given public key.
+func (r *MerchantRegistry) Remove(publicKey string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.merchants, publicKey)
+}
+
+// Get returns the merchant registered for the public key.
+func (r *MerchantRegistry) Get(publicKey string) (Merchant, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	m, ok := r.merchants[publicKey]
+	m.PreviousKeys = append([]RetiredKey(nil), m.PreviousKeys...)
+	return m, ok
+}
+
+// PublicKeys returns the sorted public keys of all registered merchants.
+func (r *MerchantRegistry) PublicKeys() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	keys := make([]string, 0, len(r.merchants))
+	for k := range r.merchants {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// signer returns the signer of the primary key of the merchant.
+func (r *MerchantRegistry) signer(publicKey string) (*signature.Signer, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	m, ok := r.merchants[publicKey]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownMerchant, publicKey)
+	}
+	return signature.New(m.PrivateKey), nil
+}
+
+// verify checks the signature against the primary key and every still valid previous key of the merchant.
+func (r *MerchantRegistry) verify(publicKey string, data string, sig string) error {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	m, ok := r.merchants[publicKey]
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrUnknownMerchant, publicKey)
+	}
+
+	err := signature.Verify(m.PrivateKey, data, sig)
+	if err == nil {
+		return nil
+	}
+
+	now := r.now()
+	for _, k := range m.PreviousKeys {
+		if k.valid(now) && signature.Verify(k.PrivateKey, data, sig) == nil {
+			return nil
+		}
+	}
+	return err
+}
@@ -0,0 +1,38 @@
+package liqpay
+
+import "net/http"
+
+// Exchange is a request to LiqPay and its outcome, as seen by Middleware.
+type Exchange struct {
+	Action   Action                 // Action of the request
+	Payload  map[string]interface{} // Payload sent as data, with version and public_key injected
+	Checkout bool                   // Checkout is set for client-server requests to the checkout endpoint
+	Request  *http.Request          // HTTP request. Middleware may change it before calling next
+	Response *http.Response         // HTTP response, set by next. Its body has been read into Body
+	Body     []byte                 // Response body, set by next
+	Result   any                    // Value the response of a server-server request is decoded into
+}
+
+// Middleware wraps every request the client sends. It is called before the request is sent and calls
+// next to send it. The error returned by next, e.g. an *APIError or *HTTPError, is the error of the
+// request unless the middleware returns another one. The Result is filled in alongside an *APIError.
+type Middleware func(ex *Exchange, next func(*Exchange) error) error
+
+// WithMiddleware appends middleware around the requests of the client. The first middleware is the outermost one.
+func WithMiddleware(mw ...Middleware) Option {
+	return func(o *clientOptions) {
+		o.middleware = append(o.middleware, mw...)
+	}
+}
+
+// exchange runs the middleware around send.
+func (c client) exchange(ex *Exchange, send func(*Exchange) error) error {
+	next := send
+	for i := len(c.middleware) - 1; i >= 0; i-- {
+		mw, inner := c.middleware[i], next
+		next = func(ex *Exchange) error {
+			return mw(ex, inner)
+		}
+	}
+	return next(ex)
+}
@@ -0,0 +1,243 @@
+// Package mock provides a configurable liqpay.Client for tests that records every call.
+//
+// Behaviour is configured per method with the ...Func fields:
+//
+//	m := mock.New()
+//	m.StatusFunc = func(orderID string) (*liqpay.StatusResponse, error) {
+//		return &liqpay.StatusResponse{OrderID: orderID, Status: liqpay.StatusSuccess}, nil
+//	}
+//
+// Methods without a function return ErrNotConfigured. A Client also satisfies every
+// narrower interface, such as liqpay.StatusClient.
+package mock
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/jim-ww/liqpay-go"
+)
+
+// ErrNotConfigured is returned by methods whose function is not set.
+var ErrNotConfigured = errors.New("liqpay mock: method not configured")
+
+var _ liqpay.Client = (*Client)(nil)
+
+// Call is a recorded method call.
+type Call struct {
+	Method string // Name of the method, e.g. "Status"
+	Args   []any  // Arguments in order
+}
+
+// Client is a mock liqpay.Client. It is safe for concurrent use, but the functions
+// must be set before the client is used.
+type Client struct {
+	CreateCheckoutFunc             func(req *liqpay.CheckoutRequest) (string, error)
+	CreateSubscriptionFunc         func(req *liqpay.SubscriptionRequest) (string, error)
+	UpdateSubscriptionFunc         func(req *liqpay.EditSubscriptionRequest) (*liqpay.SubscriptionResponse, error)
+	RemoveSubscriptionFunc         func(orderID string) (*liqpay.SubscriptionResponse, error)
+	CreateInvoiceFunc              func(req *liqpay.InvoiceRequest) (*liqpay.InvoiceResponse, error)
+	CancelInvoiceFunc              func(orderID string) (*liqpay.CancelInvoiceResponse, error)
+	StatusFunc                     func(orderID string) (*liqpay.StatusResponse, error)
+	RefundFunc                     func(orderID string, amount string) (*liqpay.RefundResponse, error)
+	ReportsFunc                    func(from time.Time, to time.Time) (*liqpay.ReportsResponse, error)
+	CompensationRegisterFunc       func(day time.Time) (*liqpay.CompensationResponse, error)
+	CompensationRegisterPeriodFunc func(from time.Time, to time.Time) (*liqpay.CompensationResponse, error)
+	PaymentCompensationFunc        func(orderID string) (*liqpay.CompensationResponse, error)
+	CallFunc                       func(action liqpay.Action, payload any, v any) error
+	ValidateCallbackFunc           func(data string, signature string) error
+	DecodeCallbackFunc             func(data string, signature string) (*liqpay.Callback, error)
+	WithMerchantFunc               func(publicKey string) (liqpay.Client, error) // Defaults to returning the mock itself
+	MerchantsFunc                  func() *liqpay.MerchantRegistry               // Defaults to an empty registry
+
+	mu        sync.Mutex
+	calls     []Call
+	merchants *liqpay.MerchantRegistry
+}
+
+// New creates a mock client with no methods configured.
+func New() *Client {
+	merchants, _ := liqpay.NewMerchantRegistry()
+	return &Client{merchants: merchants}
+}
+
+// Calls returns the recorded calls in order.
+func (m *Client) Calls() []Call {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	calls := make([]Call, len(m.calls))
+	copy(calls, m.calls)
+	return calls
+}
+
+// CallsTo returns the recorded calls of the named method in order.
+func (m *Client) CallsTo(method string) []Call {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var calls []Call
+	for _, call := range m.calls {
+		if call.Method == method {
+			calls = append(calls, call)
+		}
+	}
+	return calls
+}
+
+// Reset forgets the recorded calls.
+func (m *Client) Reset() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.calls = nil
+}
+
+func (m *Client) record(method string, args ...any) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.calls = append(m.calls, Call{Method: method, Args: args})
+}
+
+func notConfigured(method string) error {
+	return fmt.Errorf("%w: %s", ErrNotConfigured, method)
+}
+
+func (m *Client) CreateCheckout(req *liqpay.CheckoutRequest) (string, error) {
+	m.record("CreateCheckout", req)
+	if m.CreateCheckoutFunc == nil {
+		return "", notConfigured("CreateCheckout")
+	}
+	return m.CreateCheckoutFunc(req)
+}
+
+func (m *Client) CreateSubscription(req *liqpay.SubscriptionRequest) (string, error) {
+	m.record("CreateSubscription", req)
+	if m.CreateSubscriptionFunc == nil {
+		return "", notConfigured("CreateSubscription")
+	}
+	return m.CreateSubscriptionFunc(req)
+}
+
+func (m *Client) UpdateSubscription(req *liqpay.EditSubscriptionRequest) (*liqpay.SubscriptionResponse, error) {
+	m.record("UpdateSubscription", req)
+	if m.UpdateSubscriptionFunc == nil {
+		return nil, notConfigured("UpdateSubscription")
+	}
+	return m.UpdateSubscriptionFunc(req)
+}
+
+func (m *Client) RemoveSubscription(orderID string) (*liqpay.SubscriptionResponse, error) {
+	m.record("RemoveSubscription", orderID)
+	if m.RemoveSubscriptionFunc == nil {
+		return nil, notConfigured("RemoveSubscription")
+	}
+	return m.RemoveSubscriptionFunc(orderID)
+}
+
+func (m *Client) CreateInvoice(req *liqpay.InvoiceRequest) (*liqpay.InvoiceResponse, error) {
+	m.record("CreateInvoice", req)
+	if m.CreateInvoiceFunc == nil {
+		return nil, notConfigured("CreateInvoice")
+	}
+	return m.CreateInvoiceFunc(req)
+}
+
+func (m *Client) CancelInvoice(orderID string) (*liqpay.CancelInvoiceResponse, error) {
+	m.record("CancelInvoice", orderID)
+	if m.CancelInvoiceFunc == nil {
+		return nil, notConfigured("CancelInvoice")
+	}
+	return m.CancelInvoiceFunc(orderID)
+}
+
+func (m *Client) Status(orderID string) (*liqpay.StatusResponse, error) {
+	m.record("Status", orderID)
+	if m.StatusFunc == nil {
+		return nil, notConfigured("Status")
+	}
+	return m.StatusFunc(orderID)
+}
+
+func (m *Client) Refund(orderID string, amount string) (*liqpay.RefundResponse, error) {
+	m.record("Refund", orderID, amount)
+	if m.RefundFunc == nil {
+		return nil, notConfigured("Refund")
+	}
+	return m.RefundFunc(orderID, amount)
+}
+
+func (m *Client) Reports(from time.Time, to time.Time) (*liqpay.ReportsResponse, error) {
+	m.record("Reports", from, to)
+	if m.ReportsFunc == nil {
+		return nil, notConfigured("Reports")
+	}
+	return m.ReportsFunc(from, to)
+}
+
+func (m *Client) CompensationRegister(day time.Time) (*liqpay.CompensationResponse, error) {
+	m.record("CompensationRegister", day)
+	if m.CompensationRegisterFunc == nil {
+		return nil, notConfigured("CompensationRegister")
+	}
+	return m.CompensationRegisterFunc(day)
+}
+
+func (m *Client) CompensationRegisterPeriod(from time.Time, to time.Time) (*liqpay.CompensationResponse, error) {
+	m.record("CompensationRegisterPeriod", from, to)
+	if m.CompensationRegisterPeriodFunc == nil {
+		return nil, notConfigured("CompensationRegisterPeriod")
+	}
+	return m.CompensationRegisterPeriodFunc(from, to)
+}
+
+func (m *Client) PaymentCompensation(orderID string) (*liqpay.CompensationResponse, error) {
+	m.record("PaymentCompensation", orderID)
+	if m.PaymentCompensationFunc == nil {
+		return nil, notConfigured("PaymentCompensation")
+	}
+	return m.PaymentCompensationFunc(orderID)
+}
+
+func (m *Client) Call(action liqpay.Action, payload any, v any) error {
+	m.record("Call", action, payload, v)
+	if m.CallFunc == nil {
+		return notConfigured("Call")
+	}
+	return m.CallFunc(action, payload, v)
+}
+
+func (m *Client) ValidateCallback(data string, signature string) error {
+	m.record("ValidateCallback", data, signature)
+	if m.ValidateCallbackFunc == nil {
+		return notConfigured("ValidateCallback")
+	}
+	return m.ValidateCallbackFunc(data, signature)
+}
+
+func (m *Client) DecodeCallback(data string, signature string) (*liqpay.Callback, error) {
+	m.record("DecodeCallback", data, signature)
+	if m.DecodeCallbackFunc == nil {
+		return nil, notConfigured("DecodeCallback")
+	}
+	return m.DecodeCallbackFunc(data, signature)
+}
+
+func (m *Client) WithMerchant(publicKey string) (liqpay.Client, error) {
+	m.record("WithMerchant", publicKey)
+	if m.WithMerchantFunc == nil {
+		return m, nil
+	}
+	return m.WithMerchantFunc(publicKey)
+}
+
+func (m *Client) Merchants() *liqpay.MerchantRegistry {
+	m.record("Merchants")
+	if m.MerchantsFunc == nil {
+		return m.merchants
+	}
+	return m.MerchantsFunc()
+}
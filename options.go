@@ -0,0 +1,82 @@
+package liqpay
+
+import (
+	"net/http"
+	"time"
+)
+
+// Logger is used by the client to print debug messages. *log.Logger satisfies it.
+type Logger interface {
+	Printf(format string, v ...any)
+}
+
+// Option configures the client created by NewClient.
+type Option func(*clientOptions)
+
+// clientOptions collects the options passed to NewClient.
+type clientOptions struct {
+	httpClient     *http.Client
+	transport      http.RoundTripper
+	timeout        time.Duration
+	logger         Logger
+	serverURL      string
+	checkoutURL    string
+	userAgent      string
+	skipValidation bool
+	middleware     []Middleware
+}
+
+// WithHTTPClient sets the HTTP client used as a base for requests.
+// The client is copied, so the caller-owned value is never modified.
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(o *clientOptions) {
+		o.httpClient = httpClient
+	}
+}
+
+// WithTransport sets the transport used for requests.
+func WithTransport(transport http.RoundTripper) Option {
+	return func(o *clientOptions) {
+		o.transport = transport
+	}
+}
+
+// WithTimeout limits every HTTP request. It overrides Config.Timeout.
+func WithTimeout(timeout time.Duration) Option {
+	return func(o *clientOptions) {
+		o.timeout = timeout
+	}
+}
+
+// WithLogger sets the logger used for debug messages. Defaults to the standard logger.
+func WithLogger(logger Logger) Option {
+	return func(o *clientOptions) {
+		o.logger = logger
+	}
+}
+
+// WithEndpoints overrides the server-server and checkout endpoints. Empty values keep the configured ones.
+func WithEndpoints(serverURL string, checkoutURL string) Option {
+	return func(o *clientOptions) {
+		if serverURL != "" {
+			o.serverURL = serverURL
+		}
+		if checkoutURL != "" {
+			o.checkoutURL = checkoutURL
+		}
+	}
+}
+
+// WithUserAgent sets the User-Agent header sent with every request.
+func WithUserAgent(userAgent string) Option {
+	return func(o *clientOptions) {
+		o.userAgent = userAgent
+	}
+}
+
+// WithoutValidation disables the validation of requests before they are sent.
+func WithoutValidation() Option {
+	return func(o *clientOptions) {
+		o.skipValidation = true
+	}
+}
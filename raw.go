@@ -0,0 +1,125 @@
+package liqpay
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// RawFields keeps the raw JSON of a decoded response together with the fields
+// its model does not know, so newly introduced LiqPay fields are not lost.
+type RawFields struct {
+	raw   json.RawMessage
+	extra map[string]json.RawMessage
+}
+
+// RawJSON returns the JSON the value was decoded from.
+func (r RawFields) RawJSON() json.RawMessage {
+	return r.raw
+}
+
+// ExtraFields returns the fields of the decoded JSON that are not modelled by the struct.
+func (r RawFields) ExtraFields() map[string]json.RawMessage {
+	return r.extra
+}
+
+// ExtraField decodes the unmodelled field name into v. It reports whether the field was present.
+func (r RawFields) ExtraField(name string, v any) (bool, error) {
+	raw, ok := r.extra[name]
+	if !ok {
+		return false, nil
+	}
+	return true, json.Unmarshal(raw, v)
+}
+
+// unmarshalLossless decodes data into v, which must be a pointer to a struct without
+// its own UnmarshalJSON, and records the raw JSON and unknown fields in raw.
+func unmarshalLossless(data []byte, v any, raw *RawFields) error {
+	if err := json.Unmarshal(data, v); err != nil {
+		return err
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+
+	known := jsonFieldNames(reflect.TypeOf(v).Elem())
+	for name := range fields {
+		if known[name] {
+			delete(fields, name)
+		}
+	}
+
+	raw.raw = append(json.RawMessage(nil), data...)
+	raw.extra = fields
+	return nil
+}
+
+// fieldNamesCache caches the JSON field names of struct types.
+var fieldNamesCache sync.Map
+
+// jsonFieldNames returns the set of JSON field names of a struct type.
+func jsonFieldNames(t reflect.Type) map[string]bool {
+	if names, ok := fieldNamesCache.Load(t); ok {
+		return names.(map[string]bool)
+	}
+
+	names := make(map[string]bool, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+
+		name, _, _ := strings.Cut(tag, ",")
+		if f.Anonymous && name == "" && f.Type.Kind() == reflect.Struct {
+			for n := range jsonFieldNames(f.Type) {
+				names[n] = true
+			}
+			continue
+		}
+		if !f.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		names[name] = true
+	}
+
+	fieldNamesCache.Store(t, names)
+	return names
+}
+
+func (r *StatusResponse) UnmarshalJSON(data []byte) error {
+	type plain StatusResponse
+	return unmarshalLossless(data, (*plain)(r), &r.RawFields)
+}
+
+func (r *SubscriptionResponse) UnmarshalJSON(data []byte) error {
+	type plain SubscriptionResponse
+	return unmarshalLossless(data, (*plain)(r), &r.RawFields)
+}
+
+func (r *InvoiceResponse) UnmarshalJSON(data []byte) error {
+	type plain InvoiceResponse
+	return unmarshalLossless(data, (*plain)(r), &r.RawFields)
+}
+
+func (r *RefundResponse) UnmarshalJSON(data []byte) error {
+	type plain RefundResponse
+	return unmarshalLossless(data, (*plain)(r), &r.RawFields)
+}
+
+func (r *CancelInvoiceResponse) UnmarshalJSON(data []byte) error {
+	type plain CancelInvoiceResponse
+	return unmarshalLossless(data, (*plain)(r), &r.RawFields)
+}
+
+func (r *Callback) UnmarshalJSON(data []byte) error {
+	type plain Callback
+	return unmarshalLossless(data, (*plain)(r), &r.RawFields)
+}
@@ -0,0 +1,97 @@
+package liqpay
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestUnmarshalKeepsExtraFields(t *testing.T) {
+	tests := []struct {
+		name string
+		v    interface {
+			RawJSON() json.RawMessage
+			ExtraFields() map[string]json.RawMessage
+		}
+		in   string
+		want []string
+	}{
+		{"status", &StatusResponse{}, `{"status":"success","order_id":"1","amount":"10.5","bonus_type":"x","future_flag":7,"future_name":"y"}`, []string{"future_flag", "future_name"}},
+		{"refund", &RefundResponse{}, `{"status":"reversed","payment_id":1,"wait_amount":true}`, []string{"wait_amount"}},
+		{"callback", &Callback{}, `{"action":"pay","status":"success","new_field":{"a":[1,2]}}`, []string{"new_field"}},
+		{"report transaction", &ReportTransaction{}, `{"order_id":"1","status":"success"}`, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := json.Unmarshal([]byte(tt.in), tt.v); err != nil {
+				t.Fatal(err)
+			}
+			if string(tt.v.RawJSON()) != tt.in {
+				t.Errorf("expected raw JSON %s, got %s", tt.in, tt.v.RawJSON())
+			}
+
+			got := make([]string, 0)
+			for name := range tt.v.ExtraFields() {
+				got = append(got, name)
+			}
+			sort.Strings(got)
+			if want := append([]string{}, tt.want...); !reflect.DeepEqual(got, want) {
+				t.Errorf("expected extra fields %v, got %v", want, got)
+			}
+		})
+	}
+}
+
+func TestUnmarshalDecodesModelledFields(t *testing.T) {
+	var s StatusResponse
+	if err := json.Unmarshal([]byte(`{"status":"success","order_id":"1","amount":"10.5","bonus_type":"x"}`), &s); err != nil {
+		t.Fatal(err)
+	}
+	if s.Status != StatusSuccess || s.OrderID != "1" || s.Amount != 10.5 {
+		t.Errorf("expected the modelled fields to be decoded, got %+v", s)
+	}
+}
+
+func TestExtraField(t *testing.T) {
+	var cb Callback
+	if err := json.Unmarshal([]byte(`{"action":"pay","new_field":{"a":[1,2]}}`), &cb); err != nil {
+		t.Fatal(err)
+	}
+
+	var v struct {
+		A []int `json:"a"`
+	}
+	if ok, err := cb.ExtraField("new_field", &v); !ok || err != nil || !reflect.DeepEqual(v.A, []int{1, 2}) {
+		t.Errorf("expected new_field to decode, got ok=%t err=%v value=%v", ok, err, v)
+	}
+	if ok, err := cb.ExtraField("action", &v); ok || err != nil {
+		t.Errorf("expected a modelled field not to be extra, got ok=%t err=%v", ok, err)
+	}
+
+	var n int
+	if ok, err := cb.ExtraField("new_field", &n); !ok || err == nil {
+		t.Errorf("expected a decoding error, got ok=%t err=%v", ok, err)
+	}
+}
+
+func TestJSONFieldNames(t *testing.T) {
+	type embedded struct {
+		Inner string `json:"inner"`
+	}
+	type model struct {
+		embedded
+		RawFields `json:"-"`
+		Tagged    string `json:"tagged,omitempty"`
+		Untagged  string
+		Skipped   string `json:"-"`
+		private   string
+	}
+	_ = model{}.private
+
+	want := map[string]bool{"inner": true, "tagged": true, "Untagged": true}
+	if got := jsonFieldNames(reflect.TypeOf(model{})); !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+}
@@ -0,0 +1,232 @@
+// Package reconcile compares local order records with LiqPay payment data
+// (status responses, reports or callbacks) and reports the mismatches.
+package reconcile
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jim-ww/liqpay-go"
+)
+
+// Order is a local order record.
+type Order interface {
+	OrderID() string         // OrderID is the order_id sent to LiqPay
+	Amount() float64         // Amount is the order amount
+	Currency() string        // Currency is the order currency
+	Paid() bool              // Paid reports whether the order is marked as paid locally
+	RefundedAmount() float64 // RefundedAmount is the amount refunded according to local records
+}
+
+// Payment is LiqPay's view of an order.
+type Payment struct {
+	OrderID      string        `json:"order_id"`
+	PaymentID    int64         `json:"payment_id,omitempty"`
+	Status       liqpay.Status `json:"status"`
+	Amount       float64       `json:"amount"`
+	Currency     string        `json:"currency"`
+	RefundAmount float64       `json:"refund_amount,omitempty"`
+	CreateDate   time.Time     `json:"create_date"`
+	EndDate      time.Time     `json:"end_date"`
+}
+
+// FromStatus converts a status response to a Payment.
+func FromStatus(s *liqpay.StatusResponse) Payment {
+	return Payment{
+		OrderID:      s.OrderID,
+		PaymentID:    s.PaymentID.Int64(),
+		Status:       s.Status,
+		Amount:       s.Amount.Float64(),
+		Currency:     s.Currency.String(),
+		RefundAmount: s.RefundAmount.Float64(),
+		CreateDate:   s.CreateDate.Time,
+		EndDate:      s.EndDate.Time,
+	}
+}
+
+// FromReport converts a payment archive transaction to a Payment.
+func FromReport(t *liqpay.ReportTransaction) Payment {
+	return Payment{
+		OrderID:      t.OrderID,
+		PaymentID:    t.PaymentID.Int64(),
+		Status:       t.Status,
+		Amount:       t.Amount.Float64(),
+		Currency:     t.Currency.String(),
+		RefundAmount: t.RefundAmount.Float64(),
+		CreateDate:   t.CreateDate.Time,
+		EndDate:      t.EndDate.Time,
+	}
+}
+
+// FromCallback converts a callback to a Payment.
+func FromCallback(c *liqpay.Callback) Payment {
+	return Payment{
+		OrderID:      c.OrderID,
+		PaymentID:    c.PaymentID.Int64(),
+		Status:       liqpay.Status(c.Status),
+		Amount:       c.Amount.Float64(),
+		Currency:     c.Currency,
+		RefundAmount: c.RefundAmount.Float64(),
+		CreateDate:   c.CreateDate.Time,
+		EndDate:      c.EndDate.Time,
+	}
+}
+
+// Kind is the kind of a mismatch.
+type Kind string
+
+const (
+	KindPaidNotRecorded   Kind = "paid_not_recorded"   // Paid in LiqPay but not in local records
+	KindNotPaidInLiqPay   Kind = "not_paid_in_liqpay"  // Paid in local records but not in LiqPay
+	KindMissingInLiqPay   Kind = "missing_in_liqpay"   // Paid in local records, but LiqPay has no payment
+	KindUnknownOrder      Kind = "unknown_order"       // LiqPay has a payment for an order unknown locally
+	KindAmountMismatch    Kind = "amount_mismatch"     // Amounts differ
+	KindCurrencyMismatch  Kind = "currency_mismatch"   // Currencies differ
+	KindRefundNotRecorded Kind = "refund_not_recorded" // Refunded in LiqPay more than in local records
+	KindStuck             Kind = "stuck"               // Payment stays in processing or wait_* too long
+)
+
+// Mismatch is a difference between a local order and LiqPay data.
+type Mismatch struct {
+	Kind      Kind          `json:"kind"`
+	OrderID   string        `json:"order_id"`
+	PaymentID int64         `json:"payment_id,omitempty"`
+	Status    liqpay.Status `json:"status,omitempty"`
+	Local     string        `json:"local,omitempty"`  // Local value of the compared field
+	LiqPay    string        `json:"liqpay,omitempty"` // LiqPay value of the compared field
+}
+
+// Report is the result of a reconciliation.
+type Report struct {
+	Orders     int        `json:"orders"`     // Number of local orders compared
+	Payments   int        `json:"payments"`   // Number of LiqPay orders compared
+	Mismatches []Mismatch `json:"mismatches"` // Differences found, ordered by order_id
+}
+
+// Options tune the reconciliation.
+type Options struct {
+	StuckAfter time.Duration    // StuckAfter is how long a payment may stay pending. Zero reports every pending payment.
+	Now        func() time.Time // Now returns the current time. Defaults to time.Now.
+}
+
+// Reconcile compares the local orders with the LiqPay payments. When several payments
+// share an order_id, the one that changed last is used.
+func Reconcile(orders []Order, payments []Payment, opts Options) *Report {
+	now := time.Now
+	if opts.Now != nil {
+		now = opts.Now
+	}
+
+	latest := make(map[string]Payment, len(payments))
+	for _, p := range payments {
+		if prev, ok := latest[p.OrderID]; !ok || lastChange(p).After(lastChange(prev)) {
+			latest[p.OrderID] = p
+		}
+	}
+
+	report := &Report{Orders: len(orders), Payments: len(latest), Mismatches: []Mismatch{}}
+	known := make(map[string]bool, len(orders))
+
+	for _, o := range orders {
+		known[o.OrderID()] = true
+
+		p, ok := latest[o.OrderID()]
+		if !ok {
+			if o.Paid() {
+				report.add(Mismatch{Kind: KindMissingInLiqPay, OrderID: o.OrderID(), Local: "paid"})
+			}
+			continue
+		}
+
+		report.compare(o, p, now(), opts.StuckAfter)
+	}
+
+	for _, p := range latest {
+		if !known[p.OrderID] {
+			report.add(Mismatch{Kind: KindUnknownOrder, OrderID: p.OrderID, PaymentID: p.PaymentID, Status: p.Status})
+		}
+	}
+
+	sort.SliceStable(report.Mismatches, func(i, j int) bool {
+		a, b := report.Mismatches[i], report.Mismatches[j]
+		if a.OrderID != b.OrderID {
+			return a.OrderID < b.OrderID
+		}
+		return a.Kind < b.Kind
+	})
+	return report
+}
+
+// compare records the mismatches between an order and its payment.
+func (r *Report) compare(o Order, p Payment, now time.Time, stuckAfter time.Duration) {
+	mismatch := func(kind Kind, local string, remote string) {
+		r.add(Mismatch{Kind: kind, OrderID: p.OrderID, PaymentID: p.PaymentID, Status: p.Status, Local: local, LiqPay: remote})
+	}
+
+	switch paid := isPaid(p.Status); {
+	case paid && !o.Paid():
+		mismatch(KindPaidNotRecorded, "unpaid", p.Status.String())
+	case !paid && o.Paid() && isFailed(p.Status):
+		mismatch(KindNotPaidInLiqPay, "paid", p.Status.String())
+	}
+
+	if liqpay.ToKopecks(o.Amount()) != liqpay.ToKopecks(p.Amount) {
+		mismatch(KindAmountMismatch, formatAmount(o.Amount()), formatAmount(p.Amount))
+	}
+	if !strings.EqualFold(o.Currency(), p.Currency) {
+		mismatch(KindCurrencyMismatch, o.Currency(), p.Currency)
+	}
+
+	refunded := p.RefundAmount
+	if p.Status == liqpay.StatusReversed && refunded == 0 {
+		refunded = p.Amount
+	}
+	if liqpay.ToKopecks(refunded) > liqpay.ToKopecks(o.RefundedAmount()) {
+		mismatch(KindRefundNotRecorded, formatAmount(o.RefundedAmount()), formatAmount(refunded))
+	}
+
+	if isPending(p.Status) && (p.CreateDate.IsZero() || now.Sub(p.CreateDate) >= stuckAfter) {
+		mismatch(KindStuck, "", p.Status.String())
+	}
+}
+
+func (r *Report) add(m Mismatch) {
+	r.Mismatches = append(r.Mismatches, m)
+}
+
+// isPaid reports whether the status means the money was received and kept.
+// A reversed payment is compared through the refund check only.
+func isPaid(s liqpay.Status) bool {
+	switch s {
+	case liqpay.StatusSuccess, liqpay.StatusWaitCompensation, liqpay.StatusSubscribed:
+		return true
+	}
+	return false
+}
+
+// isFailed reports whether the status means the payment did not go through.
+func isFailed(s liqpay.Status) bool {
+	return s == liqpay.StatusFailure || s == liqpay.StatusError
+}
+
+// isPending reports whether the status means the payment is still in progress.
+func isPending(s liqpay.Status) bool {
+	if s == liqpay.StatusWaitCompensation {
+		return false
+	}
+	return s == liqpay.StatusProcessing || s == liqpay.StatusPrepared || strings.HasPrefix(s.String(), "wait_")
+}
+
+// lastChange returns when the payment changed last.
+func lastChange(p Payment) time.Time {
+	if p.EndDate.After(p.CreateDate) {
+		return p.EndDate
+	}
+	return p.CreateDate
+}
+
+func formatAmount(amount float64) string {
+	return strconv.FormatFloat(amount, 'f', 2, 64)
+}
@@ -0,0 +1,369 @@
+// Package recorder records LiqPay HTTP exchanges to cassette files and replays them,
+// so that integration tests can run without network access.
+//
+// A Recorder is used as the transport of the client:
+//
+//	rec, err := recorder.New("testdata/status.json", recorder.ModeAuto)
+//	...
+//	client := liqpay.NewClient(cfg, liqpay.WithTransport(rec))
+//	...
+//	err = rec.Save()
+//
+// Secrets are scrubbed from the recorded payloads and responses, and signatures are
+// not recorded at all. Requests are matched on the decoded data payload.
+package recorder
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sync"
+)
+
+// ErrInteractionNotFound is returned in replay mode when no recorded interaction matches a request.
+var ErrInteractionNotFound = errors.New("liqpay recorder: no recorded interaction matches the request")
+
+// Scrubbed replaces the values of scrubbed fields.
+const Scrubbed = "[scrubbed]"
+
+// DefaultScrubFields are the fields scrubbed from payloads and responses.
+var DefaultScrubFields = []string{
+	"public_key",
+	"private_key",
+	"card",
+	"card_cvv",
+	"card_exp_month",
+	"card_exp_year",
+	"card_token",
+	"token",
+}
+
+// Mode selects whether a Recorder records or replays.
+type Mode int
+
+const (
+	ModeReplay Mode = iota // Serve recorded interactions, never touch the network
+	ModeRecord             // Send requests and record the interactions
+	ModeAuto               // Replay if the cassette exists, record otherwise
+)
+
+// Cassette is the content of a cassette file.
+type Cassette struct {
+	Interactions []Interaction `json:"interactions"`
+}
+
+// Interaction is a recorded request and its response.
+type Interaction struct {
+	Request  Request  `json:"request"`
+	Response Response `json:"response"`
+}
+
+// Request is a recorded request.
+type Request struct {
+	Method  string          `json:"method"`
+	URL     string          `json:"url"`
+	Payload json.RawMessage `json:"payload,omitempty"` // Decoded and scrubbed data payload
+	Body    string          `json:"body,omitempty"`    // Raw body of requests without a data payload
+}
+
+// Response is a recorded response.
+type Response struct {
+	StatusCode int         `json:"status_code"`
+	Header     http.Header `json:"header,omitempty"`
+	Body       string      `json:"body"`
+}
+
+// Option configures a Recorder.
+type Option func(*Recorder)
+
+// WithTransport sets the transport used to send requests in record mode.
+func WithTransport(transport http.RoundTripper) Option {
+	return func(r *Recorder) {
+		r.transport = transport
+	}
+}
+
+// WithScrubFields adds fields to scrub from payloads and responses.
+func WithScrubFields(fields ...string) Option {
+	return func(r *Recorder) {
+		for _, field := range fields {
+			r.scrub[field] = true
+		}
+	}
+}
+
+// WithIgnoreFields excludes payload fields, such as generated order IDs or dates, from request matching.
+func WithIgnoreFields(fields ...string) Option {
+	return func(r *Recorder) {
+		for _, field := range fields {
+			r.ignore[field] = true
+		}
+	}
+}
+
+// Recorder is an http.RoundTripper that records or replays LiqPay exchanges.
+type Recorder struct {
+	path      string
+	mode      Mode
+	transport http.RoundTripper
+	scrub     map[string]bool
+	ignore    map[string]bool
+
+	mu       sync.Mutex
+	cassette Cassette
+	used     []bool
+}
+
+// New creates a recorder for the cassette at path. In replay mode the cassette is loaded immediately.
+func New(path string, mode Mode, opts ...Option) (*Recorder, error) {
+	r := &Recorder{
+		path:      path,
+		mode:      mode,
+		transport: http.DefaultTransport,
+		scrub:     make(map[string]bool, len(DefaultScrubFields)),
+		ignore:    make(map[string]bool),
+	}
+	for _, field := range DefaultScrubFields {
+		r.scrub[field] = true
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+
+	if r.mode == ModeAuto {
+		r.mode = ModeRecord
+		if _, err := os.Stat(path); err == nil {
+			r.mode = ModeReplay
+		}
+	}
+
+	if r.mode == ModeReplay {
+		raw, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("liqpay recorder: failed to read cassette: %w", err)
+		}
+		if err := json.Unmarshal(raw, &r.cassette); err != nil {
+			return nil, fmt.Errorf("liqpay recorder: failed to parse cassette %s: %w", path, err)
+		}
+		r.used = make([]bool, len(r.cassette.Interactions))
+	}
+	return r, nil
+}
+
+// Mode returns the mode the recorder runs in, with ModeAuto resolved.
+func (r *Recorder) Mode() Mode {
+	return r.mode
+}
+
+// Save writes the recorded interactions to the cassette file. It does nothing in replay mode.
+func (r *Recorder) Save() error {
+	if r.mode != ModeRecord {
+		return nil
+	}
+
+	r.mu.Lock()
+	raw, err := json.MarshalIndent(r.cassette, "", "  ")
+	r.mu.Unlock()
+	if err != nil {
+		return fmt.Errorf("liqpay recorder: failed to encode cassette: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(r.path), 0o755); err != nil {
+		return fmt.Errorf("liqpay recorder: failed to write cassette: %w", err)
+	}
+	if err := os.WriteFile(r.path, append(raw, '\n'), 0o644); err != nil {
+		return fmt.Errorf("liqpay recorder: failed to write cassette: %w", err)
+	}
+	return nil
+}
+
+// RoundTrip records or replays a single exchange.
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil {
+		var err error
+		body, err = io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("liqpay recorder: failed to read request body: %w", err)
+		}
+	}
+
+	recorded, err := r.request(req, body)
+	if err != nil {
+		return nil, err
+	}
+
+	if r.mode == ModeReplay {
+		return r.replay(req, recorded)
+	}
+	return r.record(req, body, recorded)
+}
+
+// record sends the request and stores the exchange.
+func (r *Recorder) record(req *http.Request, body []byte, recorded Request) (*http.Response, error) {
+	out := req.Clone(req.Context())
+	out.Body = io.NopCloser(bytes.NewReader(body))
+	out.ContentLength = int64(len(body))
+
+	resp, err := r.transport.RoundTrip(out)
+	if err != nil {
+		return nil, err
+	}
+	respBody, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("liqpay recorder: failed to read response body: %w", err)
+	}
+
+	header := resp.Header.Clone()
+	header.Del("Set-Cookie")
+	header.Del("Content-Length")
+
+	r.mu.Lock()
+	r.cassette.Interactions = append(r.cassette.Interactions, Interaction{
+		Request: recorded,
+		Response: Response{
+			StatusCode: resp.StatusCode,
+			Header:     header,
+			Body:       string(r.scrubJSON(respBody)),
+		},
+	})
+	r.mu.Unlock()
+
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+	return resp, nil
+}
+
+// replay serves the first unused interaction matching the request.
+func (r *Recorder) replay(req *http.Request, recorded Request) (*http.Response, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, interaction := range r.cassette.Interactions {
+		if r.used[i] || !r.matches(interaction.Request, recorded) {
+			continue
+		}
+		r.used[i] = true
+
+		resp := interaction.Response
+		header := resp.Header.Clone()
+		if header == nil {
+			header = make(http.Header)
+		}
+		return &http.Response{
+			Status:        fmt.Sprintf("%d %s", resp.StatusCode, http.StatusText(resp.StatusCode)),
+			StatusCode:    resp.StatusCode,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        header,
+			Body:          io.NopCloser(bytes.NewReader([]byte(resp.Body))),
+			ContentLength: int64(len(resp.Body)),
+			Request:       req,
+		}, nil
+	}
+
+	if len(recorded.Payload) > 0 {
+		return nil, fmt.Errorf("%w: %s %s %s", ErrInteractionNotFound, recorded.Method, recorded.URL, recorded.Payload)
+	}
+	return nil, fmt.Errorf("%w: %s %s", ErrInteractionNotFound, recorded.Method, recorded.URL)
+}
+
+// request converts a request to its recorded form, decoding and scrubbing the data payload.
+func (r *Recorder) request(req *http.Request, body []byte) (Request, error) {
+	u := *req.URL
+	u.User = nil
+	recorded := Request{Method: req.Method, URL: u.String()}
+
+	form, err := url.ParseQuery(string(body))
+	data := form.Get("data")
+	if err != nil || data == "" {
+		recorded.Body = string(body)
+		return recorded, nil
+	}
+
+	raw, err := base64.StdEncoding.DecodeString(data)
+	if err != nil {
+		return Request{}, fmt.Errorf("liqpay recorder: failed to decode base64 data: %w", err)
+	}
+	var payload map[string]interface{}
+	if err := decodeJSON(raw, &payload); err != nil {
+		return Request{}, fmt.Errorf("liqpay recorder: failed to decode data payload: %w", err)
+	}
+	r.scrubValue(payload)
+
+	recorded.Payload, err = json.Marshal(payload)
+	if err != nil {
+		return Request{}, fmt.Errorf("liqpay recorder: failed to encode payload: %w", err)
+	}
+	return recorded, nil
+}
+
+// matches reports whether a recorded request matches an incoming one.
+func (r *Recorder) matches(recorded Request, incoming Request) bool {
+	if recorded.Method != incoming.Method || recorded.URL != incoming.URL {
+		return false
+	}
+	if len(recorded.Payload) == 0 || len(incoming.Payload) == 0 {
+		return len(recorded.Payload) == len(incoming.Payload) && recorded.Body == incoming.Body
+	}
+
+	var a, b map[string]interface{}
+	if decodeJSON(recorded.Payload, &a) != nil || decodeJSON(incoming.Payload, &b) != nil {
+		return false
+	}
+	for field := range r.ignore {
+		delete(a, field)
+		delete(b, field)
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+// scrubJSON scrubs a JSON document. Other content is returned unchanged.
+func (r *Recorder) scrubJSON(raw []byte) []byte {
+	var v interface{}
+	if err := decodeJSON(raw, &v); err != nil {
+		return raw
+	}
+	r.scrubValue(v)
+
+	scrubbed, err := json.Marshal(v)
+	if err != nil {
+		return raw
+	}
+	return scrubbed
+}
+
+// scrubValue replaces the scrubbed fields in decoded JSON, at any depth.
+func (r *Recorder) scrubValue(v interface{}) {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for key, value := range v {
+			if r.scrub[key] {
+				v[key] = Scrubbed
+				continue
+			}
+			r.scrubValue(value)
+		}
+	case []interface{}:
+		for _, value := range v {
+			r.scrubValue(value)
+		}
+	}
+}
+
+// decodeJSON decodes JSON keeping numbers exact.
+func decodeJSON(raw []byte, v interface{}) error {
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+	return dec.Decode(v)
+}
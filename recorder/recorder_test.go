@@ -0,0 +1,154 @@
+package recorder
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jim-ww/liqpay-go"
+	"github.com/jim-ww/liqpay-go/signature"
+)
+
+const (
+	publicKey  = "sandbox_i00000000"
+	privateKey = "sandbox_tPvFSiHv8yBlbO6jZ3CgC0DtGrURdxhOX4AuISTR"
+)
+
+// newClient creates a client using rec as transport and sending requests to url.
+func newClient(rec *Recorder, url string) liqpay.Client {
+	cfg := &liqpay.Config{PublicKey: publicKey, PrivateKey: privateKey}
+	return liqpay.NewClient(cfg, liqpay.WithTransport(rec), liqpay.WithEndpoints(url, url))
+}
+
+func TestRecordReplay(t *testing.T) {
+	var signatures []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		signatures = append(signatures, r.PostFormValue("signature"))
+
+		var payload map[string]interface{}
+		if err := signature.Decode(r.PostFormValue("data"), &payload); err != nil {
+			t.Errorf("failed to decode request: %v", err)
+		}
+		fmt.Fprintf(w, `{"status":"success","order_id":%q,"public_key":%q,"extra":{"card_token":"secret"}}`, payload["order_id"], publicKey)
+	}))
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "cassettes", "status.json")
+	rec, err := New(path, ModeAuto)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rec.Mode() != ModeRecord {
+		t.Fatalf("expected ModeAuto to record without a cassette, got %d", rec.Mode())
+	}
+
+	client := newClient(rec, server.URL)
+	for _, orderID := range []string{"order-1", "order-2"} {
+		if _, err := client.Status(orderID); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := rec.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	secrets := append([]string{publicKey, privateKey, "secret"}, signatures...)
+	for _, secret := range secrets {
+		if strings.Contains(string(raw), secret) {
+			t.Errorf("cassette contains %q:\n%s", secret, raw)
+		}
+	}
+
+	rec, err = New(path, ModeAuto)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rec.Mode() != ModeReplay {
+		t.Fatalf("expected ModeAuto to replay an existing cassette, got %d", rec.Mode())
+	}
+
+	server.Close()
+	client = newClient(rec, server.URL)
+	for _, orderID := range []string{"order-2", "order-1"} {
+		status, err := client.Status(orderID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if status.OrderID != orderID {
+			t.Errorf("expected the response for %s, got %s", orderID, status.OrderID)
+		}
+	}
+
+	if _, err := client.Status("order-1"); !errors.Is(err, ErrInteractionNotFound) {
+		t.Errorf("expected a replayed interaction to be used once, got %v", err)
+	}
+}
+
+func TestReplayIgnoreFields(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "call.json")
+	cassette := `{"interactions":[{
+		"request":{"method":"POST","url":"http://liqpay.test","payload":{"action":"reports","date_from":1,"public_key":"[scrubbed]","version":"3"}},
+		"response":{"status_code":200,"body":"{\"result\":\"success\",\"data\":[]}"}
+	}]}`
+	if err := os.WriteFile(path, []byte(cassette), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		opts    []Option
+		wantErr error
+	}{
+		{"matched on every field", nil, ErrInteractionNotFound},
+		{"ignored field", []Option{WithIgnoreFields("date_from")}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec, err := New(path, ModeReplay, tt.opts...)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			payload := map[string]interface{}{"date_from": 2}
+			if err := newClient(rec, "http://liqpay.test").Call(liqpay.ActionReports, payload, nil); !errors.Is(err, tt.wantErr) {
+				t.Errorf("expected %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestScrubJSON(t *testing.T) {
+	rec, err := New("", ModeRecord, WithScrubFields("phone"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"top level", `{"public_key":"pk","amount":1.10}`, `{"amount":1.10,"public_key":"[scrubbed]"}`},
+		{"nested object", `{"sender":{"phone":"380000000000","name":"x"}}`, `{"sender":{"name":"x","phone":"[scrubbed]"}}`},
+		{"inside array", `{"data":[{"card_token":"t"},{"token":"t"}]}`, `{"data":[{"card_token":"[scrubbed]"},{"token":"[scrubbed]"}]}`},
+		{"not json", `<html></html>`, `<html></html>`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := string(rec.scrubJSON([]byte(tt.in))); got != tt.want {
+				t.Errorf("expected %s, got %s", tt.want, got)
+			}
+		})
+	}
+}
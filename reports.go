@@ -0,0 +1,199 @@
+package liqpay
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"io"
+	"time"
+)
+
+type ReportsRequest struct {
+	Action   Action    `json:"action"`    // Transaction type
+	DateFrom Timestamp `json:"date_from"` // Start of the period
+	DateTo   Timestamp `json:"date_to"`   // End of the period
+}
+
+type ReportsResponse struct {
+	RawFields `json:"-"` // Raw JSON and unmodelled fields
+
+	Result string              `json:"result"` // Result of the request: success or error
+	Data   []ReportTransaction `json:"data"`   // Payments of the period
+}
+
+type ReportTransaction struct {
+	RawFields `json:"-"` // Raw JSON and unmodelled fields
+
+	AcqID              FlexInt    `json:"acq_id"`              // Acquirer ID
+	Action             Action     `json:"action"`              // Transaction type
+	AgentCommission    FlexFloat  `json:"agent_commission"`    // Agent commission in payment currency
+	Amount             FlexFloat  `json:"amount"`              // Payment amount
+	AmountBonus        FlexFloat  `json:"amount_bonus"`        // Payer bonus amount in payment currency debit
+	AmountCredit       FlexFloat  `json:"amount_credit"`       // Payment amount for credit in currency of currency_credit
+	AmountDebit        FlexFloat  `json:"amount_debit"`        // Payment amount for debit in currency of currency_debit
+	CommissionCredit   FlexFloat  `json:"commission_credit"`   // Commission from the receiver in currency_credit
+	CommissionDebit    FlexFloat  `json:"commission_debit"`    // Commission from the sender in currency_debit
+	CreateDate         Timestamp  `json:"create_date"`         // Date of payment creation
+	Currency           Currency   `json:"currency"`            // Payment currency
+	CurrencyCredit     string     `json:"currency_credit"`     // Transaction currency of credit
+	CurrencyDebit      string     `json:"currency_debit"`      // Transaction currency of debit
+	Description        string     `json:"description"`         // Payment description
+	EndDate            Timestamp  `json:"end_date"`            // Date of payment edition/end
+	ErrCode            FlexString `json:"err_code"`            // Error code
+	ErrDescription     string     `json:"err_description"`     // Error description
+	Info               string     `json:"info"`                // Additional payment information
+	IP                 string     `json:"ip"`                  // Sender's IP address
+	LiqpayOrderID      string     `json:"liqpay_order_id"`     // Payment order_id in LiqPay system
+	OrderID            string     `json:"order_id"`            // Order_id payment
+	PaymentID          FlexInt    `json:"payment_id"`          // Payment id in LiqPay system
+	Paytype            string     `json:"paytype"`             // Method of payment
+	PublicKey          string     `json:"public_key"`          // Shop public key
+	ReceiverCommission FlexFloat  `json:"receiver_commission"` // Receiver commission in payment currency
+	RefundAmount       FlexFloat  `json:"refund_amount"`       // Refund amount
+	SenderCardBank     string     `json:"sender_card_bank"`    // Sender's card bank
+	SenderCardCountry  FlexInt    `json:"sender_card_country"` // Sender's card country (digital ISO 3166-1 code)
+	SenderCardMask2    string     `json:"sender_card_mask2"`   // Sender's card mask2
+	SenderCardType     string     `json:"sender_card_type"`    // Sender's card type (MC/Visa)
+	SenderCommission   FlexFloat  `json:"sender_commission"`   // Commission from the sender in the payment currency
+	SenderFirstName    string     `json:"sender_first_name"`   // Sender's first name
+	SenderLastName     string     `json:"sender_last_name"`    // Sender's last name
+	SenderPhone        string     `json:"sender_phone"`        // Sender's phone number
+	Status             Status     `json:"status"`              // Payment status
+}
+
+func (r *ReportsResponse) UnmarshalJSON(data []byte) error {
+	type plain ReportsResponse
+	return unmarshalLossless(data, (*plain)(r), &r.RawFields)
+}
+
+func (r *ReportTransaction) UnmarshalJSON(data []byte) error {
+	type plain ReportTransaction
+	return unmarshalLossless(data, (*plain)(r), &r.RawFields)
+}
+
+// Reports retrieves all payments created between from and to.
+func (c client) Reports(from time.Time, to time.Time) (*ReportsResponse, error) {
+	data := &ReportsRequest{Action: ActionReports, DateFrom: NewTimestamp(from), DateTo: NewTimestamp(to)}
+
+	req, err := c.prepareServerRequest(data)
+	if err != nil {
+		return nil, err
+	}
+
+	v := &ReportsResponse{}
+	err = c.sendServerRequest(req, v)
+	switch {
+	case err != nil && ErrorRefersToAPI(err):
+		return v, err
+	case err != nil:
+		return nil, err
+	}
+
+	return v, nil
+}
+
+// EachReport retrieves the payments between from and to in windows of at most chunk,
+// calling fn with the payments of every window in order. A non-positive chunk uses a single window.
+// Iteration stops at the first error returned by the client or by fn.
+func EachReport(c ReportClient, from time.Time, to time.Time, chunk time.Duration, fn func([]ReportTransaction) error) error {
+	if to.Before(from) {
+		return errors.New("liqpay client: report period ends before it starts")
+	}
+	if chunk <= 0 {
+		chunk = to.Sub(from)
+	}
+
+	for start := from; !start.After(to); {
+		end := start.Add(chunk)
+		if end.After(to) {
+			end = to
+		}
+
+		resp, err := c.Reports(start, end)
+		if err != nil {
+			return err
+		}
+		if err := fn(resp.Data); err != nil {
+			return err
+		}
+
+		start = end.Add(time.Millisecond)
+	}
+
+	return nil
+}
+
+// reportColumn is a column of the CSV report export.
+type reportColumn struct {
+	name  string
+	value func(t *ReportTransaction) string
+}
+
+// reportColumns are the columns of the CSV report export.
+var reportColumns = []reportColumn{
+	{"payment_id", func(t *ReportTransaction) string { return t.PaymentID.String() }},
+	{"order_id", func(t *ReportTransaction) string { return t.OrderID }},
+	{"liqpay_order_id", func(t *ReportTransaction) string { return t.LiqpayOrderID }},
+	{"action", func(t *ReportTransaction) string { return t.Action.String() }},
+	{"status", func(t *ReportTransaction) string { return t.Status.String() }},
+	{"amount", func(t *ReportTransaction) string { return t.Amount.String() }},
+	{"currency", func(t *ReportTransaction) string { return t.Currency.String() }},
+	{"refund_amount", func(t *ReportTransaction) string { return t.RefundAmount.String() }},
+	{"sender_commission", func(t *ReportTransaction) string { return t.SenderCommission.String() }},
+	{"receiver_commission", func(t *ReportTransaction) string { return t.ReceiverCommission.String() }},
+	{"agent_commission", func(t *ReportTransaction) string { return t.AgentCommission.String() }},
+	{"create_date", func(t *ReportTransaction) string { return formatReportDate(t.CreateDate) }},
+	{"end_date", func(t *ReportTransaction) string { return formatReportDate(t.EndDate) }},
+	{"description", func(t *ReportTransaction) string { return t.Description }},
+	{"paytype", func(t *ReportTransaction) string { return t.Paytype }},
+	{"sender_card_mask2", func(t *ReportTransaction) string { return t.SenderCardMask2 }},
+	{"sender_card_type", func(t *ReportTransaction) string { return t.SenderCardType }},
+	{"sender_card_bank", func(t *ReportTransaction) string { return t.SenderCardBank }},
+	{"sender_card_country", func(t *ReportTransaction) string { return t.SenderCardCountry.String() }},
+	{"err_code", func(t *ReportTransaction) string { return t.ErrCode.String() }},
+	{"err_description", func(t *ReportTransaction) string { return t.ErrDescription }},
+}
+
+// formatReportDate formats a report date in UTC, or returns an empty string if it is not set.
+func formatReportDate(t Timestamp) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(DateTimeLayout)
+}
+
+// WriteReportsCSV writes the payments as CSV with a header row. Dates are written in UTC.
+func WriteReportsCSV(w io.Writer, txs []ReportTransaction) error {
+	cw := csv.NewWriter(w)
+
+	record := make([]string, len(reportColumns))
+	for i, col := range reportColumns {
+		record[i] = col.name
+	}
+	if err := cw.Write(record); err != nil {
+		return err
+	}
+
+	for i := range txs {
+		for j, col := range reportColumns {
+			record[j] = col.value(&txs[i])
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+// WriteReportsJSON writes the payments as an indented JSON array.
+func WriteReportsJSON(w io.Writer, txs []ReportTransaction) error {
+	if txs == nil {
+		txs = []ReportTransaction{}
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(txs)
+}
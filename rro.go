@@ -0,0 +1,130 @@
+package liqpay
+
+import (
+	"fmt"
+	"net/mail"
+	"strconv"
+	"strings"
+)
+
+// Receipt builds fiscal receipt data (RROInfo), computing the cost of every item.
+type Receipt struct {
+	info RROInfo
+}
+
+// NewReceipt creates an empty receipt builder.
+func NewReceipt() *Receipt {
+	return &Receipt{}
+}
+
+// AddItem adds quantity units of the product id at the given unit price.
+// The price is rounded to kopecks first, and the item cost is computed as quantity * price,
+// rounded to kopecks, so that it matches the check of Validate.
+func (r *Receipt) AddItem(id string, quantity float64, price float64) *Receipt {
+	priceKopecks := ToKopecks(price)
+	r.info.Items = append(r.info.Items, Item{
+		Amount: quantity,
+		Cost:   formatMoney(ToKopecks(quantity * float64(priceKopecks) / 100)),
+		ID:     id,
+		Price:  formatMoney(priceKopecks),
+	})
+	return r
+}
+
+// AddDeliveryEmails adds e-mails the fiscal receipt is sent to.
+func (r *Receipt) AddDeliveryEmails(emails ...string) *Receipt {
+	r.info.DeliveryEmails = append(r.info.DeliveryEmails, emails...)
+	return r
+}
+
+// Total returns the sum of item costs, to be used as the payment amount.
+func (r *Receipt) Total() float64 {
+	total, _ := r.info.total()
+	return float64(total) / 100
+}
+
+// Build validates the receipt against the payment amount and returns it.
+func (r *Receipt) Build(amount float64) (*RROInfo, error) {
+	info := RROInfo{
+		Items:          append([]Item(nil), r.info.Items...),
+		DeliveryEmails: append([]string(nil), r.info.DeliveryEmails...),
+	}
+	if err := info.Validate(amount); err != nil {
+		return nil, err
+	}
+	return &info, nil
+}
+
+// Validate checks that every item cost equals quantity * price, that item costs
+// add up to the payment amount and that delivery e-mails are valid.
+func (i *RROInfo) Validate(amount float64) error {
+	e := &ValidationError{}
+	i.validate(e, "rro_info", amount)
+	return e.err()
+}
+
+// validate records the problems of the receipt under the field prefix.
+func (i *RROInfo) validate(e *ValidationError, prefix string, amount float64) {
+	for n, item := range i.Items {
+		field := fmt.Sprintf("%s.items[%d]", prefix, n)
+		if strings.TrimSpace(item.ID) == "" {
+			e.add(field+".id", "is required")
+		}
+		if !(item.Amount > 0) {
+			e.add(field+".amount", "must be positive")
+		}
+
+		price, err := parseMoney(item.Price)
+		if err != nil || price < 0 {
+			e.add(field+".price", "must be a non-negative number")
+			continue
+		}
+		cost, err := parseMoney(item.Cost)
+		if err != nil {
+			e.add(field+".cost", "must be a number")
+			continue
+		}
+		if expected := ToKopecks(item.Amount * float64(price) / 100); cost != expected {
+			e.add(field+".cost", "must equal amount * price ("+formatMoney(expected)+")")
+		}
+	}
+
+	if len(i.Items) > 0 {
+		if total, err := i.total(); err == nil && total != ToKopecks(amount) {
+			e.add(prefix+".items", "total "+formatMoney(total)+" does not match payment amount "+formatMoney(ToKopecks(amount)))
+		}
+	}
+
+	for n, email := range i.DeliveryEmails {
+		if addr, err := mail.ParseAddress(email); err != nil || addr.Address != email {
+			e.add(fmt.Sprintf("%s.delivery_emails[%d]", prefix, n), "must be a valid e-mail address")
+		}
+	}
+}
+
+// total returns the sum of item costs in kopecks.
+func (i *RROInfo) total() (int64, error) {
+	var total int64
+	for _, item := range i.Items {
+		cost, err := parseMoney(item.Cost)
+		if err != nil {
+			return 0, err
+		}
+		total += cost
+	}
+	return total, nil
+}
+
+// parseMoney parses a decimal amount to kopecks.
+func parseMoney(s string) (int64, error) {
+	v, err := strconv.ParseFloat(strings.TrimSpace(s), 64)
+	if err != nil {
+		return 0, err
+	}
+	return ToKopecks(v), nil
+}
+
+// formatMoney formats kopecks as a decimal amount with two fraction digits.
+func formatMoney(kopecks int64) string {
+	return strconv.FormatFloat(float64(kopecks)/100, 'f', 2, 64)
+}
@@ -0,0 +1,125 @@
+package liqpay
+
+import (
+	"errors"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+)
+
+func TestReceiptAddItem(t *testing.T) {
+	tests := []struct {
+		name      string
+		quantity  float64
+		price     float64
+		wantPrice string
+		wantCost  string
+	}{
+		{"whole quantity", 2, 10, "10.00", "20.00"},
+		{"fractional quantity", 1.5, 10, "10.00", "15.00"},
+		{"fractional quantity rounded", 0.333, 3, "3.00", "1.00"},
+		{"price rounded down", 3, 0.333, "0.33", "0.99"},
+		{"price rounded up", 0.5, 2.499, "2.50", "1.25"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := NewReceipt().AddItem("1", tt.quantity, tt.price)
+
+			info, err := r.Build(r.Total())
+			if err != nil {
+				t.Fatalf("expected a valid receipt, got %v", err)
+			}
+			item := info.Items[0]
+			if item.Price != tt.wantPrice || item.Cost != tt.wantCost {
+				t.Errorf("expected price %s and cost %s, got %s and %s", tt.wantPrice, tt.wantCost, item.Price, item.Cost)
+			}
+		})
+	}
+}
+
+func TestRROInfoValidate(t *testing.T) {
+	valid := Item{ID: "1", Amount: 2, Price: "10.00", Cost: "20.00"}
+
+	tests := []struct {
+		name   string
+		info   RROInfo
+		amount float64
+		want   []string
+	}{
+		{"valid", RROInfo{Items: []Item{valid}, DeliveryEmails: []string{"buyer@example.com"}}, 20, nil},
+		{"cost mismatch", RROInfo{Items: []Item{{ID: "1", Amount: 2, Price: "10.00", Cost: "19.99"}}}, 19.99, []string{"rro_info.items[0].cost"}},
+		{"total mismatch", RROInfo{Items: []Item{valid}}, 25, []string{"rro_info.items"}},
+		{"missing id", RROInfo{Items: []Item{{Amount: 2, Price: "10.00", Cost: "20.00"}}}, 20, []string{"rro_info.items[0].id"}},
+		{"zero quantity", RROInfo{Items: []Item{{ID: "1", Price: "10.00", Cost: "0.00"}}}, 0, []string{"rro_info.items[0].amount"}},
+		{"invalid price", RROInfo{Items: []Item{{ID: "1", Amount: 1, Price: "ten", Cost: "10.00"}}}, 10, []string{"rro_info.items[0].price"}},
+		{"invalid cost", RROInfo{Items: []Item{{ID: "1", Amount: 1, Price: "10.00", Cost: "ten"}}}, 10, []string{"rro_info.items[0].cost"}},
+		{"invalid e-mails", RROInfo{DeliveryEmails: []string{"buyer@example.com", "buyer", "Buyer <buyer@example.com>"}}, 20,
+			[]string{"rro_info.delivery_emails[1]", "rro_info.delivery_emails[2]"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assertInvalidFields(t, tt.info.Validate(tt.amount), tt.want)
+		})
+	}
+}
+
+func TestRequestsValidateRROInfo(t *testing.T) {
+	info := NewReceipt().AddItem("1", 1, 100).AddItem("2", 2, 25)
+	start := NewDateTime(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC))
+
+	tests := []struct {
+		name string
+		req  func(amount float64, info *RROInfo) interface{ Validate() error }
+	}{
+		{"checkout", func(amount float64, info *RROInfo) interface{ Validate() error } {
+			return &CheckoutRequest{Amount: amount, Currency: CurrencyUAH, Description: "order", OrderID: "1", RROInfo: info}
+		}},
+		{"invoice", func(amount float64, info *RROInfo) interface{ Validate() error } {
+			return &InvoiceRequest{Amount: amount, Currency: CurrencyUAH, Description: "order", OrderID: "1", Email: "buyer@example.com", RROInfo: info}
+		}},
+		{"subscription", func(amount float64, info *RROInfo) interface{ Validate() error } {
+			return &SubscriptionRequest{Amount: amount, Currency: CurrencyUAH, Description: "order", OrderID: "1",
+				SubscribeDateStart: start, SubscribePeriod: SubscribePeriodMonthly, RROInfo: info}
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			valid, err := info.Build(info.Total())
+			if err != nil {
+				t.Fatal(err)
+			}
+			assertInvalidFields(t, tt.req(info.Total(), valid).Validate(), nil)
+			assertInvalidFields(t, tt.req(info.Total()+1, valid).Validate(), []string{"rro_info.items"})
+		})
+	}
+}
+
+// assertInvalidFields checks that err is nil for no fields, or a *ValidationError for exactly the fields.
+func assertInvalidFields(t *testing.T, err error, fields []string) {
+	t.Helper()
+
+	if len(fields) == 0 {
+		if err != nil {
+			t.Errorf("expected no error, got %v", err)
+		}
+		return
+	}
+
+	var validationErr *ValidationError
+	if !errors.As(err, &validationErr) {
+		t.Fatalf("expected *ValidationError for %v, got %v", fields, err)
+	}
+	got := make([]string, 0, len(validationErr.Fields))
+	for f := range validationErr.Fields {
+		got = append(got, f)
+	}
+	sort.Strings(got)
+	sort.Strings(fields)
+	if !reflect.DeepEqual(got, fields) {
+		t.Errorf("expected invalid fields %v, got %v", fields, got)
+	}
+}
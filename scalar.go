@@ -0,0 +1,110 @@
+package liqpay
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// FlexInt is an integer that LiqPay may send either as a JSON number or as a string.
+type FlexInt int64
+
+// Int64 returns the value as int64.
+func (i FlexInt) Int64() int64 {
+	return int64(i)
+}
+
+func (i FlexInt) String() string {
+	return strconv.FormatInt(int64(i), 10)
+}
+
+func (i *FlexInt) UnmarshalJSON(data []byte) error {
+	text, ok := unquoteScalar(data)
+	if !ok {
+		*i = 0
+		return nil
+	}
+
+	v, err := strconv.ParseInt(text, 10, 64)
+	if err != nil {
+		f, ferr := strconv.ParseFloat(text, 64)
+		if ferr != nil || f != float64(int64(f)) {
+			return fmt.Errorf("liqpay: cannot decode %s as integer", data)
+		}
+		v = int64(f)
+	}
+	*i = FlexInt(v)
+	return nil
+}
+
+// FlexFloat is a decimal number that LiqPay may send either as a JSON number or as a string.
+type FlexFloat float64
+
+// Float64 returns the value as float64.
+func (f FlexFloat) Float64() float64 {
+	return float64(f)
+}
+
+func (f FlexFloat) String() string {
+	return strconv.FormatFloat(float64(f), 'f', -1, 64)
+}
+
+func (f *FlexFloat) UnmarshalJSON(data []byte) error {
+	text, ok := unquoteScalar(data)
+	if !ok {
+		*f = 0
+		return nil
+	}
+
+	v, err := strconv.ParseFloat(text, 64)
+	if err != nil {
+		return fmt.Errorf("liqpay: cannot decode %s as number", data)
+	}
+	*f = FlexFloat(v)
+	return nil
+}
+
+// ToKopecks converts an amount to hundredths of the currency unit, rounding half away from zero.
+// Amounts are compared in kopecks, as float values of equal amounts may differ.
+func ToKopecks(amount float64) int64 {
+	return int64(math.Round(amount * 100))
+}
+
+// FlexString is a string that LiqPay may send either as a JSON string or as a number.
+type FlexString string
+
+func (s FlexString) String() string {
+	return string(s)
+}
+
+func (s *FlexString) UnmarshalJSON(data []byte) error {
+	text, _ := unquoteScalar(data)
+	*s = FlexString(text)
+	return nil
+}
+
+// unquoteScalar returns the text of a JSON string, number or boolean.
+// It reports false for null, empty strings and values that are not scalars.
+func unquoteScalar(data []byte) (string, bool) {
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 || bytes.Equal(data, []byte("null")) {
+		return "", false
+	}
+
+	if data[0] == '"' {
+		var text string
+		if err := json.Unmarshal(data, &text); err != nil {
+			return "", false
+		}
+		text = strings.TrimSpace(text)
+		return text, text != ""
+	}
+
+	if data[0] == '{' || data[0] == '[' {
+		return "", false
+	}
+	return string(data), true
+}
@@ -0,0 +1,93 @@
+package liqpay
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestFlexIntUnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    FlexInt
+		wantErr bool
+	}{
+		{`42`, 42, false},
+		{`"42"`, 42, false},
+		{`" 42 "`, 42, false},
+		{`42.0`, 42, false},
+		{`"1.5e3"`, 1500, false},
+		{`null`, 0, false},
+		{`""`, 0, false},
+		{`{}`, 0, false},
+		{`42.5`, 0, true},
+		{`"abc"`, 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got := FlexInt(7)
+			err := json.Unmarshal([]byte(tt.in), &got)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("expected error %t, got %v", tt.wantErr, err)
+			}
+			if !tt.wantErr && got != tt.want {
+				t.Errorf("expected %d, got %d", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestFlexFloatUnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    FlexFloat
+		wantErr bool
+	}{
+		{`10.5`, 10.5, false},
+		{`"10.5"`, 10.5, false},
+		{`"10"`, 10, false},
+		{`null`, 0, false},
+		{`""`, 0, false},
+		{`[1]`, 0, false},
+		{`"ten"`, 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got := FlexFloat(7)
+			err := json.Unmarshal([]byte(tt.in), &got)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("expected error %t, got %v", tt.wantErr, err)
+			}
+			if !tt.wantErr && got != tt.want {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestFlexStringUnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		in   string
+		want FlexString
+	}{
+		{`"1234"`, "1234"},
+		{`1234`, "1234"},
+		{`12.5`, "12.5"},
+		{`true`, "true"},
+		{`null`, ""},
+		{`{"a":1}`, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got := FlexString("x")
+			if err := json.Unmarshal([]byte(tt.in), &got); err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,60 @@
+package signature
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/url"
+)
+
+// Envelope is the data/signature pair LiqPay exchanges in forms and callbacks.
+type Envelope struct {
+	Data      string `json:"data"`      // Base64 encoded JSON payload
+	Signature string `json:"signature"` // Signature of Data
+}
+
+// Values returns the envelope as form values.
+func (e Envelope) Values() url.Values {
+	return url.Values{
+		"data":      {e.Data},
+		"signature": {e.Signature},
+	}
+}
+
+// Encode encodes the payload to base64 JSON.
+func Encode(payload any) (string, error) {
+	obj, err := json.Marshal(payload)
+	if err != nil {
+		return "", fmt.Errorf("liqpay signature: failed to encode payload: %w", err)
+	}
+	return base64.StdEncoding.EncodeToString(obj), nil
+}
+
+// Decode decodes base64 JSON data into v.
+func Decode(data string, v any) error {
+	obj, err := base64.StdEncoding.DecodeString(data)
+	if err != nil {
+		return fmt.Errorf("liqpay signature: failed to decode base64 data: %w", err)
+	}
+	if err := json.Unmarshal(obj, v); err != nil {
+		return fmt.Errorf("liqpay signature: failed to decode json data: %w", err)
+	}
+	return nil
+}
+
+// Seal encodes the payload and signs it.
+func (s *Signer) Seal(payload any) (*Envelope, error) {
+	data, err := Encode(payload)
+	if err != nil {
+		return nil, err
+	}
+	return &Envelope{Data: data, Signature: s.Sign(data)}, nil
+}
+
+// Open verifies the signature and decodes data into v.
+func (s *Signer) Open(data string, signature string, v any) error {
+	if err := s.Verify(data, signature); err != nil {
+		return err
+	}
+	return Decode(data, v)
+}
@@ -0,0 +1,53 @@
+// Package signature implements LiqPay request signing and callback verification.
+//
+// It has no dependency on the HTTP client, so it can be used on its own to
+// build signed checkout data or to verify callbacks.
+package signature
+
+import (
+	"crypto/sha1"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+)
+
+// ErrInvalidSignature is returned when a signature does not match the data.
+var ErrInvalidSignature = errors.New("liqpay signature: signature mismatch")
+
+// Signer signs and verifies LiqPay data with a merchant private key.
+type Signer struct {
+	privateKey string
+}
+
+// New creates a new Signer for the given private key.
+func New(privateKey string) *Signer {
+	return &Signer{privateKey: privateKey}
+}
+
+// Sign generates a hash signature for the given base64 data.
+func (s *Signer) Sign(data string) string {
+	return Sign(s.privateKey, data)
+}
+
+// Verify checks that signature was produced for data with the signer's private key.
+func (s *Signer) Verify(data string, signature string) error {
+	return Verify(s.privateKey, data, signature)
+}
+
+// Sign generates a hash signature for the given base64 data using privateKey.
+func Sign(privateKey string, data string) string {
+	hasher := sha1.New()
+	hasher.Write([]byte(privateKey))
+	hasher.Write([]byte(data))
+	hasher.Write([]byte(privateKey))
+	return base64.StdEncoding.EncodeToString(hasher.Sum(nil))
+}
+
+// Verify checks that signature was produced for data using privateKey.
+func Verify(privateKey string, data string, signature string) error {
+	expected := Sign(privateKey, data)
+	if subtle.ConstantTimeCompare([]byte(expected), []byte(signature)) != 1 {
+		return ErrInvalidSignature
+	}
+	return nil
+}
@@ -0,0 +1,38 @@
+package signature
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestVectors(t *testing.T) {
+	for _, v := range Vectors {
+		t.Run(v.Name, func(t *testing.T) {
+			data, err := Encode(json.RawMessage(v.Payload))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if data != v.Data {
+				t.Errorf("Encode: expected %s, got %s", v.Data, data)
+			}
+
+			if sig := Sign(v.PrivateKey, v.Data); sig != v.Signature {
+				t.Errorf("Sign: expected %s, got %s", v.Signature, sig)
+			}
+			if err := Verify(v.PrivateKey, v.Data, v.Signature); err != nil {
+				t.Errorf("Verify: %v", err)
+			}
+			if err := Verify(v.PrivateKey+"x", v.Data, v.Signature); err == nil {
+				t.Error("Verify: expected an error for another private key")
+			}
+
+			var payload json.RawMessage
+			if err := Decode(v.Data, &payload); err != nil {
+				t.Fatal(err)
+			}
+			if string(payload) != v.Payload {
+				t.Errorf("Decode: expected %s, got %s", v.Payload, payload)
+			}
+		})
+	}
+}
@@ -0,0 +1,35 @@
+package signature
+
+// Vector is a known-good signing example.
+type Vector struct {
+	Name       string // Short description of the case
+	PrivateKey string // Private key used for signing
+	Payload    string // JSON payload before encoding
+	Data       string // Base64 encoded Payload
+	Signature  string // Expected signature of Data
+}
+
+// Vectors are published test vectors for implementations of LiqPay signing.
+var Vectors = []Vector{
+	{
+		Name:       "checkout",
+		PrivateKey: "a4825234f4bae72a0be04eafe9e8e2bada209255",
+		Payload:    `{"action":"pay","amount":"3","currency":"UAH","description":"test","order_id":"000001","public_key":"i00000000","version":"3"}`,
+		Data:       "eyJhY3Rpb24iOiJwYXkiLCJhbW91bnQiOiIzIiwiY3VycmVuY3kiOiJVQUgiLCJkZXNjcmlwdGlvbiI6InRlc3QiLCJvcmRlcl9pZCI6IjAwMDAwMSIsInB1YmxpY19rZXkiOiJpMDAwMDAwMDAiLCJ2ZXJzaW9uIjoiMyJ9",
+		Signature:  "vdPdbrGOEslNtQAVdYzN+Pmlt8w=",
+	},
+	{
+		Name:       "status",
+		PrivateKey: "sandbox_tPvFSiHv8yBlbO6jZ3CgC0DtGrURdxhOX4AuISTR",
+		Payload:    `{"action":"status","order_id":"order-42","public_key":"sandbox_i00000000","version":"3"}`,
+		Data:       "eyJhY3Rpb24iOiJzdGF0dXMiLCJvcmRlcl9pZCI6Im9yZGVyLTQyIiwicHVibGljX2tleSI6InNhbmRib3hfaTAwMDAwMDAwIiwidmVyc2lvbiI6IjMifQ==",
+		Signature:  "gePr6y0dhEcWpjJRDvO0jA2izSc=",
+	},
+	{
+		Name:       "callback",
+		PrivateKey: "sandbox_tPvFSiHv8yBlbO6jZ3CgC0DtGrURdxhOX4AuISTR",
+		Payload:    `{"action":"pay","amount":100.5,"currency":"UAH","order_id":"order-42","payment_id":1234567890,"public_key":"sandbox_i00000000","status":"success","version":3}`,
+		Data:       "eyJhY3Rpb24iOiJwYXkiLCJhbW91bnQiOjEwMC41LCJjdXJyZW5jeSI6IlVBSCIsIm9yZGVyX2lkIjoib3JkZXItNDIiLCJwYXltZW50X2lkIjoxMjM0NTY3ODkwLCJwdWJsaWNfa2V5Ijoic2FuZGJveF9pMDAwMDAwMDAiLCJzdGF0dXMiOiJzdWNjZXNzIiwidmVyc2lvbiI6M30=",
+		Signature:  "rSPJkl8F8f+UIk+4449k0dwgEes=",
+	},
+}
@@ -0,0 +1,210 @@
+// Package simulator builds realistic signed LiqPay callbacks and posts them to webhook
+// handlers, so that callback processing can be exercised offline.
+package simulator
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/jim-ww/liqpay-go"
+	"github.com/jim-ww/liqpay-go/signature"
+)
+
+// Payment describes the order a callback is simulated for.
+type Payment struct {
+	OrderID     string          // Order ID in your shop
+	Amount      float64         // Payment amount
+	Currency    liqpay.Currency // Payment currency. Defaults to UAH
+	Description string          // Payment description
+	Action      liqpay.Action   // Type of operation. Defaults to subscribe for subscription statuses, refund for reversed and pay otherwise
+	Customer    string          // Customer identifier on merchant's site
+}
+
+// Option configures a Simulator.
+type Option func(*Simulator)
+
+// WithHTTPClient sets the HTTP client used to post callbacks.
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(s *Simulator) {
+		s.httpClient = httpClient
+	}
+}
+
+// WithNow sets the clock used for payment dates.
+func WithNow(now func() time.Time) Option {
+	return func(s *Simulator) {
+		s.now = now
+	}
+}
+
+// Simulator plays the LiqPay side of the callback exchange for one merchant.
+type Simulator struct {
+	publicKey  string
+	signer     *signature.Signer
+	httpClient *http.Client
+	now        func() time.Time
+	paymentID  int64
+}
+
+// New creates a simulator signing callbacks with the merchant's keys.
+func New(publicKey string, privateKey string, opts ...Option) *Simulator {
+	s := &Simulator{
+		publicKey:  publicKey,
+		signer:     signature.New(privateKey),
+		httpClient: http.DefaultClient,
+		now:        time.Now,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	s.paymentID = s.now().Unix()
+	return s
+}
+
+// Callback builds the callback LiqPay sends for a payment reaching the status.
+// Failed payments carry err_code, err_erc and err_description; refunded ones carry the refund fields.
+// The returned callback can be adjusted before it is sealed or posted.
+func (s *Simulator) Callback(status liqpay.Status, p Payment) *liqpay.Callback {
+	now := s.now().UTC()
+	paymentID := atomic.AddInt64(&s.paymentID, 1)
+
+	action := p.Action
+	if action == "" {
+		switch status {
+		case liqpay.StatusSubscribed, liqpay.StatusUnsubscribed:
+			action = liqpay.ActionSubscribe
+		case liqpay.StatusReversed:
+			action = liqpay.ActionRefund
+		default:
+			action = liqpay.ActionPay
+		}
+	}
+
+	currency := string(p.Currency)
+	if currency == "" {
+		currency = string(liqpay.CurrencyUAH)
+	}
+
+	commission := math.Round(p.Amount*1.5) / 100
+
+	cb := &liqpay.Callback{
+		AcqID:              414963,
+		Action:             action,
+		Amount:             liqpay.FlexFloat(p.Amount),
+		AmountCredit:       liqpay.FlexFloat(p.Amount),
+		AmountDebit:        liqpay.FlexFloat(p.Amount),
+		CommissionCredit:   liqpay.FlexFloat(commission),
+		CreateDate:         liqpay.NewTimestamp(now.Add(-time.Minute)),
+		Currency:           currency,
+		CurrencyCredit:     currency,
+		CurrencyDebit:      currency,
+		Customer:           p.Customer,
+		Description:        p.Description,
+		EndDate:            liqpay.NewTimestamp(now),
+		IP:                 "127.0.0.1",
+		LiqpayOrderID:      fmt.Sprintf("SIM%013d", paymentID),
+		MpiEci:             "7",
+		OrderID:            p.OrderID,
+		PaymentID:          liqpay.FlexInt(paymentID),
+		Paytype:            "card",
+		PublicKey:          s.publicKey,
+		ReceiverCommission: liqpay.FlexFloat(commission),
+		SenderCardBank:     "Test Bank",
+		SenderCardCountry:  804,
+		SenderCardMask2:    "424242*42",
+		SenderCardType:     "visa",
+		Status:             string(status),
+		Type:               "buy",
+		Version:            3,
+	}
+
+	switch status {
+	case liqpay.StatusSuccess, liqpay.StatusSubscribed, liqpay.StatusWaitCompensation:
+		cb.AuthcodeDebit = fmt.Sprintf("%06d", paymentID%1000000)
+		cb.RRNDebit = fmt.Sprintf("%012d", paymentID)
+		cb.CompletionDate = liqpay.NewTimestamp(now)
+	case liqpay.StatusFailure:
+		cb.ErrCode = liqpay.FlexString(strconv.Itoa(int(liqpay.FinancialPaymentDeclined)))
+		cb.ErrErc = cb.ErrCode
+		cb.ErrDescription = "Payment declined. Try again later"
+	case liqpay.StatusError:
+		cb.ErrCode = liqpay.FlexString(liqpay.NonFinancialParameterIncorrect)
+		cb.ErrErc = cb.ErrCode
+		cb.ErrDescription = "Parameter specified incorrectly"
+	case liqpay.StatusReversed:
+		cb.AuthcodeDebit = fmt.Sprintf("%06d", paymentID%1000000)
+		cb.RRNDebit = fmt.Sprintf("%012d", paymentID)
+		cb.CompletionDate = liqpay.NewTimestamp(now.Add(-time.Minute))
+		cb.RefundAmount = liqpay.FlexFloat(p.Amount)
+		cb.RefundDateLast = liqpay.NewTimestamp(now)
+	case liqpay.Status3DSVerify:
+		cb.Is3DS = true
+		cb.MpiEci = "5"
+		cb.RedirectTo = "https://www.liqpay.ua/api/3/checkout/3ds?token=" + cb.LiqpayOrderID
+	}
+	return cb
+}
+
+// Seal encodes and signs the callback the way LiqPay does. Empty fields are omitted from the payload.
+func (s *Simulator) Seal(cb *liqpay.Callback) (*signature.Envelope, error) {
+	raw, err := json.Marshal(cb)
+	if err != nil {
+		return nil, fmt.Errorf("liqpay simulator: failed to encode callback: %w", err)
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &fields); err != nil {
+		return nil, fmt.Errorf("liqpay simulator: failed to encode callback: %w", err)
+	}
+	for name, value := range fields {
+		if name != "is_3ds" && unset(value) {
+			delete(fields, name)
+		}
+	}
+
+	return s.signer.Seal(fields)
+}
+
+// Post sends the callback to url as a data/signature form, as LiqPay does with server_url.
+// The caller must close the response body.
+func (s *Simulator) Post(url string, cb *liqpay.Callback) (*http.Response, error) {
+	envelope, err := s.Seal(cb)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, url, strings.NewReader(envelope.Values().Encode()))
+	if err != nil {
+		return nil, fmt.Errorf("liqpay simulator: failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("liqpay simulator: request failed: %w", err)
+	}
+	return resp, nil
+}
+
+// Send builds the callback for the payment reaching the status and posts it to url.
+// The caller must close the response body.
+func (s *Simulator) Send(url string, status liqpay.Status, p Payment) (*http.Response, error) {
+	return s.Post(url, s.Callback(status, p))
+}
+
+// unset reports whether a callback field holds the zero value of its type, i.e. was not set
+// by Callback or the caller. LiqPay leaves such fields out of callbacks.
+func unset(v json.RawMessage) bool {
+	switch string(bytes.TrimSpace(v)) {
+	case "", "null", `""`, "0", "false":
+		return true
+	}
+	return false
+}
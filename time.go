@@ -0,0 +1,90 @@
+package liqpay
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// DateTimeLayout is the layout of dates in LiqPay requests, always in UTC.
+const DateTimeLayout = "2006-01-02 15:04:05"
+
+// DateTime is a request date encoded as "2016-04-24 00:00:00" in UTC.
+type DateTime struct {
+	time.Time
+}
+
+// NewDateTime converts t to a DateTime in UTC.
+func NewDateTime(t time.Time) *DateTime {
+	return &DateTime{Time: t.UTC()}
+}
+
+func (d DateTime) String() string {
+	return d.UTC().Format(DateTimeLayout)
+}
+
+func (d DateTime) MarshalJSON() ([]byte, error) {
+	return []byte(strconv.Quote(d.String())), nil
+}
+
+func (d *DateTime) UnmarshalJSON(data []byte) error {
+	t, err := parseDate(data)
+	if err != nil {
+		return err
+	}
+	d.Time = t
+	return nil
+}
+
+// Timestamp is a response date encoded as milliseconds since the Unix epoch.
+type Timestamp struct {
+	time.Time
+}
+
+// NewTimestamp converts t to a Timestamp in UTC.
+func NewTimestamp(t time.Time) Timestamp {
+	return Timestamp{Time: t.UTC()}
+}
+
+// Milliseconds returns the timestamp as milliseconds since the Unix epoch, or zero if it is not set.
+func (t Timestamp) Milliseconds() int64 {
+	if t.IsZero() {
+		return 0
+	}
+	return t.UnixNano() / int64(time.Millisecond)
+}
+
+func (t Timestamp) MarshalJSON() ([]byte, error) {
+	return []byte(strconv.FormatInt(t.Milliseconds(), 10)), nil
+}
+
+func (t *Timestamp) UnmarshalJSON(data []byte) error {
+	v, err := parseDate(data)
+	if err != nil {
+		return err
+	}
+	t.Time = v
+	return nil
+}
+
+// parseDate decodes a LiqPay date given either as milliseconds since the Unix epoch
+// or in DateTimeLayout. Null, empty and zero values decode to the zero time.
+func parseDate(data []byte) (time.Time, error) {
+	text, ok := unquoteScalar(data)
+	if !ok {
+		return time.Time{}, nil
+	}
+
+	if ms, err := strconv.ParseInt(text, 10, 64); err == nil {
+		if ms == 0 {
+			return time.Time{}, nil
+		}
+		return time.Unix(0, ms*int64(time.Millisecond)).UTC(), nil
+	}
+
+	t, err := time.ParseInLocation(DateTimeLayout, text, time.UTC)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("liqpay: cannot decode %s as date", data)
+	}
+	return t, nil
+}
@@ -0,0 +1,85 @@
+package liqpay
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestParseDate(t *testing.T) {
+	want := time.Date(2016, 4, 24, 10, 30, 15, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		in      string
+		want    time.Time
+		wantErr bool
+	}{
+		{"layout", `"2016-04-24 10:30:15"`, want, false},
+		{"milliseconds", `1461493815000`, want, false},
+		{"milliseconds as string", `"1461493815000"`, want, false},
+		{"milliseconds with fraction", `1461493815123`, want.Add(123 * time.Millisecond), false},
+		{"zero", `0`, time.Time{}, false},
+		{"zero as string", `"0"`, time.Time{}, false},
+		{"null", `null`, time.Time{}, false},
+		{"empty", `""`, time.Time{}, false},
+		{"other layout", `"2016-04-24T10:30:15Z"`, time.Time{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var ts Timestamp
+			err := json.Unmarshal([]byte(tt.in), &ts)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Timestamp: expected error %t, got %v", tt.wantErr, err)
+			}
+			if !ts.Equal(tt.want) {
+				t.Errorf("Timestamp: expected %v, got %v", tt.want, ts.Time)
+			}
+
+			var dt DateTime
+			err = json.Unmarshal([]byte(tt.in), &dt)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("DateTime: expected error %t, got %v", tt.wantErr, err)
+			}
+			if !dt.Equal(tt.want) {
+				t.Errorf("DateTime: expected %v, got %v", tt.want, dt.Time)
+			}
+		})
+	}
+}
+
+func TestDateTimeMarshalJSON(t *testing.T) {
+	kyiv := time.FixedZone("EEST", 3*60*60)
+
+	raw, err := json.Marshal(NewDateTime(time.Date(2016, 4, 24, 3, 0, 0, 0, kyiv)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `"2016-04-24 00:00:00"`; string(raw) != want {
+		t.Errorf("expected %s in UTC, got %s", want, raw)
+	}
+}
+
+func TestTimestampMarshalJSON(t *testing.T) {
+	tests := []struct {
+		name string
+		in   Timestamp
+		want string
+	}{
+		{"set", NewTimestamp(time.Date(2016, 4, 24, 10, 30, 15, 123e6, time.UTC)), `1461493815123`},
+		{"zero", Timestamp{}, `0`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			raw, err := json.Marshal(tt.in)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(raw) != tt.want {
+				t.Errorf("expected %s, got %s", tt.want, raw)
+			}
+		})
+	}
+}
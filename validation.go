@@ -0,0 +1,175 @@
+package liqpay
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	maxOrderIDLength = 255 // Maximum length of order_id
+	maxURLLength     = 510 // Maximum length of result_url and server_url
+)
+
+// ValidationError lists the problems found in a request, grouped by JSON field name.
+type ValidationError struct {
+	Fields map[string][]string
+}
+
+func (e *ValidationError) Error() string {
+	fields := make([]string, 0, len(e.Fields))
+	for f := range e.Fields {
+		fields = append(fields, f)
+	}
+	sort.Strings(fields)
+
+	problems := make([]string, 0, len(fields))
+	for _, f := range fields {
+		problems = append(problems, f+": "+strings.Join(e.Fields[f], ", "))
+	}
+	return "liqpay client: invalid request: " + strings.Join(problems, "; ")
+}
+
+// add records a problem with the field.
+func (e *ValidationError) add(field string, problem string) {
+	if e.Fields == nil {
+		e.Fields = make(map[string][]string)
+	}
+	e.Fields[field] = append(e.Fields[field], problem)
+}
+
+// err returns e if any problem was recorded, nil otherwise.
+func (e *ValidationError) err() error {
+	if len(e.Fields) == 0 {
+		return nil
+	}
+	return e
+}
+
+func (e *ValidationError) required(field string, value string) {
+	if strings.TrimSpace(value) == "" {
+		e.add(field, "is required")
+	}
+}
+
+func (e *ValidationError) orderID(value string) {
+	e.required("order_id", value)
+	if utf8.RuneCountInString(value) > maxOrderIDLength {
+		e.add("order_id", "must be at most "+strconv.Itoa(maxOrderIDLength)+" characters")
+	}
+}
+
+func (e *ValidationError) url(field string, value string) {
+	if utf8.RuneCountInString(value) > maxURLLength {
+		e.add(field, "must be at most "+strconv.Itoa(maxURLLength)+" characters")
+	}
+}
+
+func (e *ValidationError) amount(value float64) {
+	if !(value > 0) {
+		e.add("amount", "must be positive")
+	}
+}
+
+func (e *ValidationError) currency(value Currency) {
+	if !value.IsValid() {
+		e.add("currency", "must be one of USD, EUR, UAH")
+	}
+}
+
+func (e *ValidationError) language(value Language) {
+	if value != "" && !value.IsValid() {
+		e.add("language", "must be one of uk, en")
+	}
+}
+
+// Validate checks the request before it is sent.
+func (r *CheckoutRequest) Validate() error {
+	e := &ValidationError{}
+	e.amount(r.Amount)
+	e.currency(r.Currency)
+	e.required("description", r.Description)
+	e.orderID(r.OrderID)
+	e.language(r.Language)
+	for _, p := range r.PayTypes {
+		if !p.IsValid() {
+			e.add("pay_types", "unknown pay type "+strconv.Quote(p.String()))
+		}
+	}
+	e.url("result_url", r.ResultURL)
+	e.url("server_url", r.ServerURL)
+	if r.RROInfo != nil {
+		r.RROInfo.validate(e, "rro_info", r.Amount)
+	}
+	return e.err()
+}
+
+// Validate checks the request before it is sent.
+func (r *InvoiceRequest) Validate() error {
+	e := &ValidationError{}
+	e.amount(r.Amount)
+	e.currency(r.Currency)
+	e.required("description", r.Description)
+	e.orderID(r.OrderID)
+	if strings.TrimSpace(r.Email) == "" && strings.TrimSpace(r.Phone) == "" {
+		e.add("email", "phone or email is required")
+		e.add("phone", "phone or email is required")
+	}
+	switch Action(r.ActionPayment) {
+	case "", ActionPay, ActionHold, ActionSubscribe, ActionPayDonate:
+	default:
+		e.add("action_payment", "must be one of pay, hold, subscribe, paydonate")
+	}
+	e.language(r.Language)
+	e.url("result_url", r.ResultURL)
+	e.url("server_url", r.ServerURL)
+	if r.RROInfo != nil {
+		r.RROInfo.validate(e, "rro_info", r.Amount)
+	}
+	return e.err()
+}
+
+// Validate checks the request before it is sent.
+func (r *SubscriptionRequest) Validate() error {
+	e := &ValidationError{}
+	e.amount(r.Amount)
+	e.currency(r.Currency)
+	e.required("description", r.Description)
+	e.orderID(r.OrderID)
+	if r.SubscribeDateStart == nil {
+		e.add("subscribe_date_start", "is required")
+	}
+	if !r.SubscribePeriod.IsValid() {
+		e.add("subscribe_periodicity", "must be one of day, week, month, year")
+	}
+	e.language(r.Language)
+	e.url("result_url", r.ResultURL)
+	e.url("server_url", r.ServerURL)
+	if r.RROInfo != nil {
+		r.RROInfo.validate(e, "rro_info", r.Amount)
+	}
+	return e.err()
+}
+
+// Validate checks the request before it is sent.
+func (r *EditSubscriptionRequest) Validate() error {
+	e := &ValidationError{}
+	e.amount(r.Amount)
+	e.currency(Currency(r.Currency))
+	e.required("description", r.Description)
+	e.orderID(r.OrderID)
+	return e.err()
+}
+
+// Validate checks the request before it is sent.
+func (r *RefundRequest) Validate() error {
+	e := &ValidationError{}
+	e.orderID(r.OrderID)
+	if amount, err := strconv.ParseFloat(strings.TrimSpace(r.Amount), 64); err != nil {
+		e.add("amount", "must be a number")
+	} else {
+		e.amount(amount)
+	}
+	return e.err()
+}
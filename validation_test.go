@@ -0,0 +1,69 @@
+package liqpay
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestValidate(t *testing.T) {
+	longID := strings.Repeat("x", maxOrderIDLength+1)
+	longURL := "https://example.com/" + strings.Repeat("x", maxURLLength)
+	start := NewDateTime(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC))
+
+	tests := []struct {
+		name string
+		req  interface{ Validate() error }
+		want []string
+	}{
+		{"valid checkout", &CheckoutRequest{Amount: 1, Currency: CurrencyUAH, Description: "order", OrderID: "1", Language: LanguageUK,
+			PayTypes: []PayType{PayTypeCard}, ResultURL: "https://example.com"}, nil},
+		{"empty checkout", &CheckoutRequest{}, []string{"amount", "currency", "description", "order_id"}},
+		{"checkout limits", &CheckoutRequest{Amount: -1, Currency: "RUB", Description: " ", OrderID: longID, Language: "de",
+			PayTypes: []PayType{"bitcoin"}, ResultURL: longURL, ServerURL: longURL},
+			[]string{"amount", "currency", "description", "order_id", "language", "pay_types", "result_url", "server_url"}},
+		{"valid invoice", &InvoiceRequest{Amount: 1, Currency: CurrencyUSD, Description: "order", OrderID: "1", Phone: "380000000000"}, nil},
+		{"invoice without contact", &InvoiceRequest{Amount: 1, Currency: CurrencyUSD, Description: "order", OrderID: "1", ActionPayment: "refund"},
+			[]string{"email", "phone", "action_payment"}},
+		{"valid subscription", &SubscriptionRequest{Amount: 1, Currency: CurrencyEUR, Description: "order", OrderID: "1",
+			SubscribeDateStart: start, SubscribePeriod: SubscribePeriodYearly}, nil},
+		{"subscription without schedule", &SubscriptionRequest{Amount: 1, Currency: CurrencyEUR, Description: "order", OrderID: "1", SubscribePeriod: "hour"},
+			[]string{"subscribe_date_start", "subscribe_periodicity"}},
+		{"edit subscription", &EditSubscriptionRequest{Currency: "UAH"}, []string{"amount", "description", "order_id"}},
+		{"valid refund", &RefundRequest{OrderID: "1", Amount: " 10.50 "}, nil},
+		{"refund amount not a number", &RefundRequest{OrderID: "1", Amount: "ten"}, []string{"amount"}},
+		{"refund amount not positive", &RefundRequest{Amount: "0"}, []string{"amount", "order_id"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assertInvalidFields(t, tt.req.Validate(), tt.want)
+		})
+	}
+}
+
+func TestValidationErrorMessage(t *testing.T) {
+	err := (&CheckoutRequest{Amount: 1, Currency: CurrencyUAH, Language: "de"}).Validate()
+
+	want := "liqpay client: invalid request: description: is required; language: must be one of uk, en; order_id: is required"
+	if err == nil || err.Error() != want {
+		t.Errorf("expected %q, got %v", want, err)
+	}
+}
+
+func TestValidationErrorGroupsProblems(t *testing.T) {
+	err := (&RefundRequest{OrderID: " " + strings.Repeat("x", maxOrderIDLength), Amount: "1"}).Validate()
+
+	validationErr, ok := err.(*ValidationError)
+	if !ok {
+		t.Fatalf("expected *ValidationError, got %v", err)
+	}
+	if problems := validationErr.Fields["order_id"]; len(problems) != 1 {
+		t.Errorf("expected one problem for order_id, got %v", problems)
+	}
+
+	err = (&RefundRequest{OrderID: strings.Repeat(" ", maxOrderIDLength+1), Amount: "1"}).Validate()
+	if problems := err.(*ValidationError).Fields["order_id"]; len(problems) != 2 {
+		t.Errorf("expected both problems for order_id, got %v", problems)
+	}
+}
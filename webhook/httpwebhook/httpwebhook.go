@@ -0,0 +1,21 @@
+// Package httpwebhook serves LiqPay callbacks with net/http.
+package httpwebhook
+
+import (
+	"net/http"
+
+	"github.com/jim-ww/liqpay-go/webhook"
+)
+
+// Handler returns a handler serving callbacks posted to it with the router.
+// Requests with other methods are answered with 405.
+func Handler(r *webhook.Router) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.Method != http.MethodPost {
+			w.Header().Set("Allow", http.MethodPost)
+			http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+			return
+		}
+		r.ServeHTTP(w, req)
+	})
+}
@@ -0,0 +1,81 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"runtime/debug"
+	"time"
+
+	"github.com/jim-ww/liqpay-go"
+	"github.com/jim-ww/liqpay-go/dedup"
+)
+
+// Logging logs every callback with its outcome and duration.
+func Logging(logger liqpay.Logger) Middleware {
+	return func(next Handler) Handler {
+		return HandlerFunc(func(ctx context.Context, cb *liqpay.Callback) error {
+			start := time.Now()
+			err := next.HandleCallback(ctx, cb)
+			if err != nil {
+				logger.Printf("[LIQPAY CALLBACK] action=%s status=%s order_id=%s payment_id=%d duration=%s error=%v",
+					cb.Action, cb.Status, cb.OrderID, cb.PaymentID.Int64(), time.Since(start), err)
+				return err
+			}
+			logger.Printf("[LIQPAY CALLBACK] action=%s status=%s order_id=%s payment_id=%d duration=%s",
+				cb.Action, cb.Status, cb.OrderID, cb.PaymentID.Int64(), time.Since(start))
+			return nil
+		})
+	}
+}
+
+// Recover turns a panic in a handler into an error, answered with 500.
+func Recover() Middleware {
+	return func(next Handler) Handler {
+		return HandlerFunc(func(ctx context.Context, cb *liqpay.Callback) (err error) {
+			defer func() {
+				if v := recover(); v != nil {
+					err = fmt.Errorf("liqpay webhook: handler panicked: %v\n%s", v, debug.Stack())
+				}
+			}()
+			return next.HandleCallback(ctx, cb)
+		})
+	}
+}
+
+// Dedup acknowledges callbacks the guard has already seen without calling the handler,
+// and rejects stale ones with 400. When the handler fails or panics, the callback is forgotten,
+// so that LiqPay can deliver it again.
+func Dedup(guard *dedup.Guard) Middleware {
+	return func(next Handler) Handler {
+		return HandlerFunc(func(ctx context.Context, cb *liqpay.Callback) error {
+			switch err := guard.Check(cb); {
+			case errors.Is(err, dedup.ErrDuplicate):
+				return nil
+			case errors.Is(err, dedup.ErrStale):
+				return Error(http.StatusBadRequest, err)
+			case err != nil:
+				return err
+			}
+
+			returned := false
+			defer func() {
+				if !returned {
+					// The handler panicked. The panic goes on to Recover or the server.
+					_ = guard.Forget(cb)
+				}
+			}()
+
+			err := next.HandleCallback(ctx, cb)
+			returned = true
+			if err != nil {
+				if ferr := guard.Forget(cb); ferr != nil {
+					return fmt.Errorf("%w; %v", err, ferr)
+				}
+				return err
+			}
+			return nil
+		})
+	}
+}
@@ -0,0 +1,50 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/jim-ww/liqpay-go"
+	"github.com/jim-ww/liqpay-go/dedup"
+)
+
+func TestDedupForgetsFailedCallbacks(t *testing.T) {
+	tests := []struct {
+		name string
+		fail func() error
+	}{
+		{"error", func() error { return errors.New("database is down") }},
+		{"panic", func() error { panic("database is down") }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := 0
+			r := NewRouter(nil)
+			r.Use(Recover(), Dedup(dedup.New(nil, dedup.NewMemoryStore())))
+			r.Fallback(HandlerFunc(func(context.Context, *liqpay.Callback) error {
+				calls++
+				if calls == 1 {
+					return tt.fail()
+				}
+				return nil
+			}))
+
+			cb := &liqpay.Callback{Action: liqpay.ActionPay, Status: string(liqpay.StatusSuccess), PaymentID: 1}
+			if code := StatusCode(r.Dispatch(context.Background(), cb)); code != http.StatusInternalServerError {
+				t.Fatalf("expected 500 for the first delivery, got %d", code)
+			}
+			if err := r.Dispatch(context.Background(), cb); err != nil {
+				t.Fatalf("expected the redelivery to succeed, got %v", err)
+			}
+			if err := r.Dispatch(context.Background(), cb); err != nil {
+				t.Fatalf("expected the duplicate to be acknowledged, got %v", err)
+			}
+			if calls != 2 {
+				t.Errorf("expected the handler to run twice, got %d", calls)
+			}
+		})
+	}
+}
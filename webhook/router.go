@@ -0,0 +1,209 @@
+// Package webhook routes LiqPay callbacks to handlers registered per action and status.
+//
+//	r := webhook.NewRouter(client)
+//	r.Use(webhook.Logging(logger), webhook.Recover(), webhook.Dedup(guard))
+//	r.HandleFunc(liqpay.ActionPay, liqpay.StatusSuccess, markPaid)
+//	r.HandleFunc(liqpay.ActionSubscribe, liqpay.StatusUnsubscribed, cancelSubscription)
+//	r.HandleFunc(webhook.AnyAction, liqpay.StatusFailure, notifyFailure)
+//	http.Handle("/liqpay/callback", r)
+//
+// The error returned by the handler decides the HTTP response, see StatusCode.
+// A Router is an http.Handler, so it serves net/http and the routers built on it, e.g. chi.
+// Other frameworks pass the posted form values to ServeCallback and respond with StatusCode.
+package webhook
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+
+	"github.com/jim-ww/liqpay-go"
+)
+
+const (
+	AnyAction liqpay.Action = "" // AnyAction matches callbacks of every action
+	AnyStatus liqpay.Status = "" // AnyStatus matches callbacks of every status
+)
+
+// Handler processes a verified callback.
+type Handler interface {
+	HandleCallback(ctx context.Context, cb *liqpay.Callback) error
+}
+
+// HandlerFunc adapts a function to a Handler.
+type HandlerFunc func(ctx context.Context, cb *liqpay.Callback) error
+
+func (f HandlerFunc) HandleCallback(ctx context.Context, cb *liqpay.Callback) error {
+	return f(ctx, cb)
+}
+
+// Middleware wraps a Handler.
+type Middleware func(Handler) Handler
+
+type route struct {
+	action liqpay.Action
+	status liqpay.Status
+}
+
+// Router dispatches callbacks to the handler registered for their action and status.
+// A handler for the exact action and status is preferred, then one for any action with the status,
+// then one for the action with any status, then one for any action and status, then the fallback.
+type Router struct {
+	verifier liqpay.CallbackVerifier
+
+	mu           sync.RWMutex
+	routes       map[route]Handler
+	fallback     Handler
+	middleware   []Middleware
+	errorHandler func(w http.ResponseWriter, r *http.Request, err error)
+}
+
+// NewRouter creates a router verifying callbacks with v. Callbacks without a matching
+// handler are acknowledged and ignored until a fallback is set.
+func NewRouter(v liqpay.CallbackVerifier) *Router {
+	return &Router{
+		verifier:     v,
+		routes:       make(map[route]Handler),
+		fallback:     HandlerFunc(func(context.Context, *liqpay.Callback) error { return nil }),
+		errorHandler: WriteError,
+	}
+}
+
+// Single creates a router passing every callback verified with v to h.
+func Single(v liqpay.CallbackVerifier, h Handler) *Router {
+	r := NewRouter(v)
+	r.Fallback(h)
+	return r
+}
+
+// Handle registers the handler for callbacks with the action and status. AnyAction and AnyStatus act as wildcards.
+func (r *Router) Handle(action liqpay.Action, status liqpay.Status, h Handler) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.routes[route{action, status}] = h
+}
+
+// HandleFunc registers the handler function for callbacks with the action and status.
+func (r *Router) HandleFunc(action liqpay.Action, status liqpay.Status, fn func(ctx context.Context, cb *liqpay.Callback) error) {
+	r.Handle(action, status, HandlerFunc(fn))
+}
+
+// Fallback sets the handler for callbacks no other handler matches.
+func (r *Router) Fallback(h Handler) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.fallback = h
+}
+
+// Use appends middleware. The first middleware added is the outermost one.
+func (r *Router) Use(mw ...Middleware) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.middleware = append(r.middleware, mw...)
+}
+
+// ErrorHandler sets the function writing the HTTP response for a failed callback. Defaults to WriteError.
+func (r *Router) ErrorHandler(fn func(w http.ResponseWriter, r *http.Request, err error)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.errorHandler = fn
+}
+
+// Dispatch passes a verified callback through the middleware to its handler.
+func (r *Router) Dispatch(ctx context.Context, cb *liqpay.Callback) error {
+	r.mu.RLock()
+	h := r.match(cb.Action, liqpay.Status(cb.Status))
+	for i := len(r.middleware) - 1; i >= 0; i-- {
+		h = r.middleware[i](h)
+	}
+	r.mu.RUnlock()
+
+	return h.HandleCallback(ctx, cb)
+}
+
+// match returns the handler for the action and status.
+func (r *Router) match(action liqpay.Action, status liqpay.Status) Handler {
+	for _, key := range []route{{action, status}, {AnyAction, status}, {action, AnyStatus}, {AnyAction, AnyStatus}} {
+		if h, ok := r.routes[key]; ok {
+			return h
+		}
+	}
+	return r.fallback
+}
+
+// ServeHTTP verifies the callback posted by LiqPay as a data/signature form and dispatches it.
+func (r *Router) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	r.mu.RLock()
+	errorHandler := r.errorHandler
+	r.mu.RUnlock()
+
+	if err := r.ServeCallback(req.Context(), req.PostFormValue("data"), req.PostFormValue("signature")); err != nil {
+		errorHandler(w, req, err)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+// ServeCallback verifies the callback data and signature and dispatches the callback.
+// It is the entry point for adapters of web frameworks.
+func (r *Router) ServeCallback(ctx context.Context, data string, signature string) error {
+	if data == "" || signature == "" {
+		return Error(http.StatusBadRequest, ErrMissingCallback)
+	}
+
+	cb, err := r.verifier.DecodeCallback(data, signature)
+	if err != nil {
+		return Error(http.StatusBadRequest, err)
+	}
+	return r.Dispatch(ctx, cb)
+}
+
+// ErrMissingCallback is returned when a request carries no callback data or signature.
+var ErrMissingCallback = errors.New("liqpay webhook: missing data or signature")
+
+// StatusError is an error with the HTTP status code to respond with.
+type StatusError struct {
+	Code int
+	Err  error
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("liqpay webhook: %d: %v", e.Code, e.Err)
+}
+
+func (e *StatusError) Unwrap() error {
+	return e.Err
+}
+
+// Error wraps err with the HTTP status code to respond with. Handlers return it e.g. to reject a
+// callback with a 4xx status, as any other error is answered with 500 and LiqPay delivers the callback again.
+func Error(code int, err error) error {
+	return &StatusError{Code: code, Err: err}
+}
+
+// StatusCode returns the HTTP status code for the result of a callback: 200 for nil,
+// the code of a StatusError, and 500 for any other error.
+func StatusCode(err error) int {
+	if err == nil {
+		return http.StatusOK
+	}
+
+	var statusErr *StatusError
+	if errors.As(err, &statusErr) {
+		return statusErr.Code
+	}
+	return http.StatusInternalServerError
+}
+
+// WriteError responds with the status code of err and its status text. The error itself
+// is not exposed to the caller.
+func WriteError(w http.ResponseWriter, _ *http.Request, err error) {
+	code := StatusCode(err)
+	http.Error(w, http.StatusText(code), code)
+}
@@ -0,0 +1,62 @@
+package webhook
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jim-ww/liqpay-go"
+)
+
+func TestRouterPrecedence(t *testing.T) {
+	levels := []struct {
+		name   string
+		action liqpay.Action
+		status liqpay.Status
+	}{
+		{"exact", liqpay.ActionPay, liqpay.StatusSuccess},
+		{"any action", AnyAction, liqpay.StatusSuccess},
+		{"any status", liqpay.ActionPay, AnyStatus},
+		{"any action and status", AnyAction, AnyStatus},
+		{"fallback", "", ""},
+	}
+
+	for i, want := range levels {
+		t.Run(want.name, func(t *testing.T) {
+			var got string
+			handler := func(name string) Handler {
+				return HandlerFunc(func(context.Context, *liqpay.Callback) error {
+					got = name
+					return nil
+				})
+			}
+
+			r := NewRouter(nil)
+			for _, l := range levels[i:] {
+				if l.name == "fallback" {
+					r.Fallback(handler(l.name))
+					continue
+				}
+				r.Handle(l.action, l.status, handler(l.name))
+			}
+			// Handlers for other actions and statuses must never match.
+			r.Handle(liqpay.ActionRefund, liqpay.StatusSuccess, handler("other action"))
+			r.Handle(liqpay.ActionPay, liqpay.StatusFailure, handler("other status"))
+
+			cb := &liqpay.Callback{Action: liqpay.ActionPay, Status: string(liqpay.StatusSuccess)}
+			if err := r.Dispatch(context.Background(), cb); err != nil {
+				t.Fatal(err)
+			}
+			if got != want.name {
+				t.Errorf("expected the %s handler, got %q", want.name, got)
+			}
+		})
+	}
+}
+
+func TestRouterWithoutHandlerAcknowledges(t *testing.T) {
+	r := NewRouter(nil)
+	cb := &liqpay.Callback{Action: liqpay.ActionPay, Status: string(liqpay.StatusSuccess)}
+	if err := r.Dispatch(context.Background(), cb); err != nil {
+		t.Errorf("expected nil, got %v", err)
+	}
+}